@@ -0,0 +1,106 @@
+package database_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/driver005/database"
+)
+
+type Sale struct {
+	ID        uint
+	Region    string
+	Amount    float64
+	Quantity  int
+	DeletedAt database.DeletedAt
+}
+
+func TestAggregate(t *testing.T) {
+	db := openDB(t, &Sale{})
+	sales := []Sale{
+		{Region: "east", Amount: 10, Quantity: 1},
+		{Region: "east", Amount: 20, Quantity: 2},
+		{Region: "west", Amount: 30, Quantity: 3},
+		{Region: "west", Amount: 100, Quantity: 10},
+	}
+	if err := db.Create(&sales).Error; err != nil {
+		t.Fatalf("no error should happen when create sales, got %v", err)
+	}
+	db.Delete(&sales[3])
+
+	var (
+		sum, avg, max float64
+		min           int
+	)
+	db.Model(&Sale{}).Sum("amount", &sum)
+	db.Model(&Sale{}).Avg("Amount", &avg)
+	db.Model(&Sale{}).Max("amount", &max)
+	db.Model(&Sale{}).Where("region = ?", "west").Min("quantity", &min)
+	if sum != 60 || avg != 20 || max != 30 || min != 3 {
+		t.Errorf("deleted sales shouldn't be aggregated, got sum %v, avg %v, max %v, min %v", sum, avg, max, min)
+	}
+
+	var total int
+	db.Model(&Sale{}).Scopes(func(db *database.DB) *database.DB {
+		return db.Where("region = ?", "east")
+	}).Sum("amount * quantity", &total)
+	if total != 50 {
+		t.Errorf("expressions should be aggregated with scopes, got %v", total)
+	}
+
+	var joined float64
+	db.Model(&Sale{}).Joins("JOIN sales AS others ON others.id = sales.id AND others.region = ?", "east").Sum("sales.amount", &joined)
+	if joined != 30 {
+		t.Errorf("joined sales should be aggregated, got %v", joined)
+	}
+
+	sum = 1
+	if result := db.Model(&Sale{}).Where("region = ?", "north").Sum("amount", &sum); result.Error != nil || sum != 0 {
+		t.Errorf("NULL results of empty sets should be zero, got %v, error %v", sum, result.Error)
+	}
+
+	totals := map[string]float64{}
+	result := db.Model(&Sale{}).Group("region").Order("region").Sum("amount", &totals)
+	if result.Error != nil || result.RowsAffected != 2 || totals["east"] != 30 || totals["west"] != 30 {
+		t.Errorf("grouped sums should be scanned into map, got %v, error %v", totals, result.Error)
+	}
+
+	region := func(db *database.DB) *database.DB {
+		return db.Group("region").Order("region")
+	}
+
+	totals = map[string]float64{}
+	if result := db.Model(&Sale{}).Scopes(region).Sum("amount", &totals); result.Error != nil || len(totals) != 2 || totals["east"] != 30 {
+		t.Errorf("sums grouped by scopes should be scanned into map, got %v, error %v", totals, result.Error)
+	}
+
+	sql := db.ToSQL(func(tx *database.DB) *database.DB {
+		return tx.Model(&Sale{}).Scopes(func(db *database.DB) *database.DB {
+			return db.Order("id")
+		}).Sum("amount", &sum)
+	})
+	if !strings.Contains(sql, "SUM(") || strings.Contains(sql, "ORDER BY") {
+		t.Errorf("ORDER BY of scopes should be dropped without GROUP BY, got %v", sql)
+	}
+
+	var counts map[string]*int
+	db.Unscoped().Model(&Sale{}).Group("region").Max("quantity", &counts)
+	if len(counts) != 2 || *counts["west"] != 10 {
+		t.Errorf("grouped maximums should be scanned into a new map, got %v", counts)
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	db := openDB(t, &Sale{})
+
+	var sum float64
+	if err := db.Model(&Sale{}).Sum("amount", sum).Error; !errors.Is(err, database.ErrInvalidData) {
+		t.Errorf("non-pointer destinations should be rejected, got %v", err)
+	}
+
+	totals := map[string]float64{}
+	if err := db.Model(&Sale{}).Sum("amount", &totals).Error; !errors.Is(err, database.ErrInvalidData) {
+		t.Errorf("maps without GROUP BY should be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package database_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+)
+
+type Shipment struct {
+	ShopID    uint `database:"primaryKey;autoIncrement:false"`
+	Number    uint `database:"primaryKey;autoIncrement:false"`
+	Processed bool
+}
+
+type Ticket struct {
+	ID        uint
+	Processed bool
+}
+
+type shipmentKey struct {
+	ShopID, Number uint
+}
+
+func seedShipments(t *testing.T) *database.DB {
+	db := openDB(t, &Shipment{})
+
+	var shipments []Shipment
+	for shop := uint(1); shop <= 3; shop++ {
+		for number := uint(1); number <= 4; number++ {
+			shipments = append(shipments, Shipment{ShopID: shop, Number: number, Processed: number == 2})
+		}
+	}
+
+	if err := db.Create(&shipments).Error; err != nil {
+		t.Fatalf("no error should happen when create shipments, got %v", err)
+	}
+	return db
+}
+
+func shipmentKeys(shipments []Shipment) (keys []shipmentKey) {
+	for _, shipment := range shipments {
+		keys = append(keys, shipmentKey{shipment.ShopID, shipment.Number})
+	}
+	return
+}
+
+func TestFindInBatchesWithOptions(t *testing.T) {
+	db := seedShipments(t)
+
+	var (
+		visited []shipmentKey
+		batches []int
+	)
+	result := db.FindInBatchesWithOptions(&[]Shipment{}, database.BatchOptions{BatchSize: 5}, func(tx *database.DB, batch int) error {
+		visited = append(visited, shipmentKeys(*tx.Statement.Dest.(*[]Shipment))...)
+		batches = append(batches, batch)
+		return nil
+	})
+	if result.Error != nil || result.RowsAffected != 12 {
+		t.Fatalf("no error should happen when find in batches, got %v, rows %v", result.Error, result.RowsAffected)
+	}
+
+	if !reflect.DeepEqual(batches, []int{1, 2, 3}) {
+		t.Errorf("batches expects [1 2 3], got %v", batches)
+	}
+
+	var expects []shipmentKey
+	for shop := uint(1); shop <= 3; shop++ {
+		for number := uint(1); number <= 4; number++ {
+			expects = append(expects, shipmentKey{shop, number})
+		}
+	}
+
+	if !reflect.DeepEqual(visited, expects) {
+		t.Errorf("composite primary keys should be paged in order, expects %v, got %v", expects, visited)
+	}
+}
+
+func TestFindInBatchesWithSingleKey(t *testing.T) {
+	db := openDB(t, &Ticket{})
+
+	tickets := make([]Ticket, 10)
+	for idx := range tickets {
+		tickets[idx].Processed = idx%3 == 0
+	}
+	if err := db.Create(&tickets).Error; err != nil {
+		t.Fatalf("no error should happen when create tickets, got %v", err)
+	}
+
+	for _, desc := range []bool{false, true} {
+		var visited []uint
+		err := db.Where("processed = ?", false).FindInBatchesWithOptions(&[]Ticket{}, database.BatchOptions{
+			BatchSize: 2,
+			Keys:      []clause.OrderByColumn{{Column: clause.Column{Name: "id"}, Desc: desc}},
+		}, func(tx *database.DB, batch int) error {
+			if batch > len(tickets) {
+				return errors.New("batches should end")
+			}
+
+			for _, ticket := range *tx.Statement.Dest.(*[]Ticket) {
+				visited = append(visited, ticket.ID)
+			}
+			return nil
+		}).Error
+		if err != nil {
+			t.Fatalf("no error should happen when find in batches, got %v", err)
+		}
+
+		// the keyset is ANDed with the caller's conditions
+		expects := []uint{2, 3, 5, 6, 8, 9}
+		if desc {
+			expects = []uint{9, 8, 6, 5, 3, 2}
+		}
+		if !reflect.DeepEqual(visited, expects) {
+			t.Errorf("tickets expects %v, got %v", expects, visited)
+		}
+	}
+}
+
+func TestFindInBatchesWithWorkers(t *testing.T) {
+	db := seedShipments(t)
+
+	var (
+		mu      sync.Mutex
+		visited []shipmentKey
+	)
+	result := db.FindInBatchesWithOptions(&[]Shipment{}, database.BatchOptions{BatchSize: 2, Workers: 3}, func(tx *database.DB, batch int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		visited = append(visited, shipmentKeys(*tx.Statement.Dest.(*[]Shipment))...)
+		return nil
+	})
+	if result.Error != nil || result.RowsAffected != 12 {
+		t.Fatalf("no error should happen when find in batches, got %v, rows %v", result.Error, result.RowsAffected)
+	}
+
+	sort.Slice(visited, func(i, j int) bool {
+		return visited[i].ShopID < visited[j].ShopID || visited[i].ShopID == visited[j].ShopID && visited[i].Number < visited[j].Number
+	})
+	for idx := 1; idx < len(visited); idx++ {
+		if visited[idx] == visited[idx-1] {
+			t.Errorf("shipment %v visited twice", visited[idx])
+		}
+	}
+
+	if len(visited) != 12 {
+		t.Errorf("all shipments should be visited, got %v", visited)
+	}
+}
+
+func TestFindInBatchesAbort(t *testing.T) {
+	for _, workers := range []int{0, 3} {
+		db := seedShipments(t)
+		errAbort := errors.New("abort")
+
+		var (
+			mu    sync.Mutex
+			calls int
+		)
+		err := db.FindInBatchesWithOptions(&[]Shipment{}, database.BatchOptions{BatchSize: 1, Workers: workers}, func(tx *database.DB, batch int) error {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+
+			if batch == 2 {
+				return errAbort
+			}
+
+			// other workers are slower than the failing one, so the remaining batches are skipped
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}).Error
+
+		if !errors.Is(err, errAbort) {
+			t.Errorf("error of fc should be returned with %v workers, got %v", workers, err)
+		}
+
+		if calls >= 12 {
+			t.Errorf("remaining batches should be skipped with %v workers, got %v calls", workers, calls)
+		}
+	}
+
+	db := seedShipments(t)
+	if err := db.FindInBatchesWithOptions(&[]Shipment{}, database.BatchOptions{}, func(*database.DB, int) error { return nil }).Error; !errors.Is(err, database.ErrInvalidData) {
+		t.Errorf("expects ErrInvalidData without batch size, got %v", err)
+	}
+
+	err := db.FindInBatchesWithOptions(&[]Shipment{}, database.BatchOptions{BatchSize: 1, Keys: []clause.OrderByColumn{{Column: clause.Column{Name: "unknown"}}}}, func(*database.DB, int) error { return nil }).Error
+	if !errors.Is(err, database.ErrInvalidField) {
+		t.Errorf("expects ErrInvalidField with unknown keys, got %v", err)
+	}
+}
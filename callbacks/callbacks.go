@@ -3,10 +3,10 @@ package callbacks
 import "github.com/driver005/database"
 
 var (
-	createClauses = []string{"INSERT", "VALUES", "ON CONFLICT"}
-	queryClauses  = []string{"SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR"}
-	updateClauses = []string{"UPDATE", "SET", "WHERE"}
-	deleteClauses = []string{"DELETE", "FROM", "WHERE"}
+	createClauses = []string{"WITH", "INSERT", "VALUES", "ON CONFLICT"}
+	queryClauses  = []string{"WITH", "SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR"}
+	updateClauses = []string{"WITH", "UPDATE", "SET", "WHERE"}
+	deleteClauses = []string{"WITH", "DELETE", "FROM", "WHERE"}
 )
 
 type Config struct {
@@ -42,6 +42,7 @@ func RegisterDefaultCallbacks(db *database.DB, config *Config) {
 	createCallback.Register("database:create", Create(config))
 	createCallback.Register("database:save_after_associations", SaveAfterAssociations(true))
 	createCallback.Register("database:after_create", AfterCreate)
+	createCallback.Register("database:register_transaction_hooks", RegisterTransactionHooks)
 	createCallback.Match(enableTransaction).Register("database:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 	createCallback.Clauses = config.CreateClauses
 
@@ -57,6 +58,7 @@ func RegisterDefaultCallbacks(db *database.DB, config *Config) {
 	deleteCallback.Register("database:delete_before_associations", DeleteBeforeAssociations)
 	deleteCallback.Register("database:delete", Delete(config))
 	deleteCallback.Register("database:after_delete", AfterDelete)
+	deleteCallback.Register("database:register_transaction_hooks", RegisterTransactionHooks)
 	deleteCallback.Match(enableTransaction).Register("database:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 	deleteCallback.Clauses = config.DeleteClauses
 
@@ -68,6 +70,7 @@ func RegisterDefaultCallbacks(db *database.DB, config *Config) {
 	updateCallback.Register("database:update", Update(config))
 	updateCallback.Register("database:save_after_associations", SaveAfterAssociations(false))
 	updateCallback.Register("database:after_update", AfterUpdate)
+	updateCallback.Register("database:register_transaction_hooks", RegisterTransactionHooks)
 	updateCallback.Match(enableTransaction).Register("database:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 	updateCallback.Clauses = config.UpdateClauses
 
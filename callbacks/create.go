@@ -10,6 +10,8 @@ import (
 	"github.com/driver005/database/utils"
 )
 
+var versionType = reflect.TypeOf(database.Version{})
+
 // BeforeCreate before create hooks
 func BeforeCreate(db *database.DB) {
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && (db.Statement.Schema.BeforeSave || db.Statement.Schema.BeforeCreate) {
@@ -315,6 +317,12 @@ func ConvertToCreateValues(stmt *database.Statement) (values clause.Values) {
 									}
 
 									onConflict.DoUpdates = append(onConflict.DoUpdates, assignment)
+								} else if field.IndirectFieldType == versionType {
+									// increase versions of existing records instead of overwriting them
+									onConflict.DoUpdates = append(onConflict.DoUpdates, clause.Assignment{
+										Column: clause.Column{Name: field.DBName},
+										Value:  clause.Expr{SQL: "? + 1", Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: field.DBName}}},
+									})
 								} else {
 									columns = append(columns, column.Name)
 								}
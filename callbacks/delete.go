@@ -30,6 +30,14 @@ func DeleteBeforeAssociations(db *database.DB) {
 			return
 		}
 
+		// associations are soft deleted at the time of the record, so they can be restored with it
+		softDeleted := !db.Statement.Unscoped && len(db.Statement.Schema.DeleteClauses) > 0
+		deletedAt, ok := db.Statement.Settings.Load(database.SoftDeleteTimeKey)
+		if !ok {
+			deletedAt = db.NowFunc()
+			db.Statement.Settings.Store(database.SoftDeleteTimeKey, deletedAt)
+		}
+
 		for column, v := range selectColumns {
 			if !v {
 				continue
@@ -44,7 +52,7 @@ func DeleteBeforeAssociations(db *database.DB) {
 			case schema.HasOne, schema.HasMany:
 				queryConds := rel.ToQueryConditions(db.Statement.Context, db.Statement.ReflectValue)
 				modelValue := reflect.New(rel.FieldSchema.ModelType).Interface()
-				tx := db.Session(&database.Session{NewDB: true}).Model(modelValue)
+				tx := db.Session(&database.Session{NewDB: true}).Set(database.SoftDeleteTimeKey, deletedAt).Model(modelValue)
 				withoutConditions := false
 				if db.Statement.Unscoped {
 					tx = tx.Unscoped()
@@ -76,13 +84,17 @@ func DeleteBeforeAssociations(db *database.DB) {
 					return
 				}
 			case schema.Many2Many:
+				if softDeleted && db.KeepJoinRowsOnSoftDelete {
+					continue
+				}
+
 				var (
 					queryConds     = make([]clause.Expression, 0, len(rel.References))
 					foreignFields  = make([]*schema.Field, 0, len(rel.References))
 					relForeignKeys = make([]string, 0, len(rel.References))
 					modelValue     = reflect.New(rel.JoinTable.ModelType).Interface()
 					table          = rel.JoinTable.Table
-					tx             = db.Session(&database.Session{NewDB: true}).Model(modelValue).Table(table)
+					tx             = db.Session(&database.Session{NewDB: true}).Set(database.SoftDeleteTimeKey, deletedAt).Model(modelValue).Table(table)
 				)
 
 				for _, ref := range rel.References {
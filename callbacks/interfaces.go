@@ -37,3 +37,11 @@ type AfterDeleteInterface interface {
 type AfterFindInterface interface {
 	AfterFind(*database.DB) error
 }
+
+type AfterCommitInterface interface {
+	AfterCommit(*database.DB) error
+}
+
+type AfterRollbackInterface interface {
+	AfterRollback(*database.DB) error
+}
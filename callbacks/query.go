@@ -11,7 +11,7 @@ import (
 )
 
 func Query(db *database.DB) {
-	if db.Error == nil {
+	if _, scanned := db.InstanceGet("database:scanned"); db.Error == nil && !scanned {
 		BuildQuerySQL(db)
 
 		if !db.DryRun && db.Error == nil {
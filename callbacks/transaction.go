@@ -6,6 +6,9 @@ func BeginTransaction(db *database.DB) {
 	if !db.Config.SkipDefaultTransaction && db.Error == nil {
 		if tx := db.Begin(); tx.Error == nil {
 			db.Statement.ConnPool = tx.Statement.ConnPool
+			if hooks, ok := tx.Statement.Settings.Load(database.TxHooksKey); ok {
+				db.Statement.Settings.Store(database.TxHooksKey, hooks)
+			}
 			db.InstanceSet("database:started_transaction", true)
 		} else if tx.Error == database.ErrInvalidTransaction {
 			tx.Error = nil
@@ -28,3 +31,37 @@ func CommitOrRollbackTransaction(db *database.DB) {
 		}
 	}
 }
+
+// RegisterTransactionHooks registers AfterCommit and AfterRollback hooks of values on the current transaction
+func RegisterTransactionHooks(db *database.DB) {
+	if db.Statement.Schema != nil && !db.Statement.SkipHooks && (db.Statement.Schema.AfterCommit || db.Statement.Schema.AfterRollback) {
+		// hooks run after the transaction finished, so they get a db using the connection pool
+		tx := db.Session(&database.Session{NewDB: true, Context: db.Statement.Context})
+		tx.Statement.ConnPool = db.ConnPool
+
+		callMethod(db, func(value interface{}, _ *database.DB) (called bool) {
+			if db.Statement.Schema.AfterCommit && db.Error == nil {
+				if i, ok := value.(AfterCommitInterface); ok {
+					called = true
+					db.AfterCommit(func() {
+						if err := i.AfterCommit(tx); err != nil {
+							tx.Logger.Error(tx.Statement.Context, "AfterCommit hook failed: %v", err)
+						}
+					})
+				}
+			}
+
+			if db.Statement.Schema.AfterRollback {
+				if i, ok := value.(AfterRollbackInterface); ok {
+					called = true
+					db.AfterRollback(func() {
+						if err := i.AfterRollback(tx); err != nil {
+							tx.Logger.Error(tx.Statement.Context, "AfterRollback hook failed: %v", err)
+						}
+					})
+				}
+			}
+			return called
+		})
+	}
+}
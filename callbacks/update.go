@@ -72,6 +72,11 @@ func Update(config *Config) func(db *database.DB) {
 			db.Statement.AddClauseIfNotExists(clause.Update{})
 			if _, ok := db.Statement.Clauses["SET"]; !ok {
 				if set := ConvertToAssignments(db.Statement); len(set) != 0 {
+					if c, ok := db.Statement.Clauses["optimistic_lock_enabled"]; ok {
+						if versionSet, ok := c.Expression.(clause.Set); ok {
+							set = append(set, versionSet...)
+						}
+					}
 					db.Statement.AddClause(set)
 				} else {
 					return
@@ -99,6 +104,18 @@ func Update(config *Config) func(db *database.DB) {
 					db.RowsAffected, _ = result.RowsAffected()
 				}
 			}
+
+			if c, ok := db.Statement.Clauses["optimistic_lock_enabled"]; ok && db.Error == nil {
+				if db.RowsAffected == 0 {
+					db.AddError(database.ErrStaleObject)
+				} else if versionSet, ok := c.Expression.(clause.Set); ok && db.Statement.ReflectValue.CanAddr() {
+					for _, assignment := range versionSet {
+						if field := db.Statement.Schema.LookUpField(assignment.Column.Name); field != nil {
+							db.AddError(field.Set(db.Statement.Context, db.Statement.ReflectValue, assignment.Value))
+						}
+					}
+				}
+			}
 		}
 	}
 }
@@ -42,7 +42,65 @@ func (db *DB) Clauses(conds ...clause.Expression) (tx *DB) {
 	return
 }
 
-var tableRegexp = regexp.MustCompile(`(?i).+? AS (\w+)\s*(?:$|,)`)
+var (
+	tableRegexp = regexp.MustCompile(`(?i).+? AS (\w+)\s*(?:$|,)`)
+	cteRegexp   = regexp.MustCompile(`^\s*(\w+)\s*(?:\((.*)\))?\s*$`)
+)
+
+// With add a common table expression, query could be a `*DB` subquery, a clause.Expression or raw SQL with args
+//
+//	db.With("active_users", db.Model(&User{}).Where("active = ?", true)).Table("active_users").Find(&users)
+//	db.With("totals (user_id, amount)", "SELECT user_id, SUM(amount) FROM orders GROUP BY user_id").Table("totals").Find(&totals)
+func (db *DB) With(name string, query interface{}, args ...interface{}) (tx *DB) {
+	return db.with(false, name, query, args...)
+}
+
+// WithRecursive add a recursive common table expression
+//
+//	db.WithRecursive("subordinates (id, manager_id)",
+//		"? UNION ALL ?",
+//		db.Model(&Employee{}).Select("id", "manager_id").Where("id = ?", 1),
+//		db.Model(&Employee{}).Select("employees.id", "employees.manager_id").Joins("JOIN subordinates ON employees.manager_id = subordinates.id"),
+//	).Table("subordinates").Find(&employees)
+func (db *DB) WithRecursive(name string, query interface{}, args ...interface{}) (tx *DB) {
+	return db.with(true, name, query, args...)
+}
+
+func (db *DB) with(recursive bool, name string, query interface{}, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+
+	results := cteRegexp.FindStringSubmatch(name)
+	if len(results) != 3 {
+		tx.AddError(fmt.Errorf("%w: invalid with name %s", ErrInvalidData, name))
+		return
+	}
+
+	cte := clause.CTE{Name: results[1]}
+	if results[2] != "" {
+		for _, column := range strings.Split(results[2], ",") {
+			cte.Columns = append(cte.Columns, strings.TrimSpace(column))
+		}
+	}
+
+	switch v := query.(type) {
+	case *DB:
+		cte.Query = v
+	case clause.Expression:
+		cte.Query = v
+	case string:
+		if strings.Contains(v, "@") && len(args) > 0 {
+			cte.Query = clause.NamedExpr{SQL: v, Vars: args}
+		} else {
+			cte.Query = clause.Expr{SQL: v, Vars: args}
+		}
+	default:
+		tx.AddError(fmt.Errorf("%w: unsupported with query %v", ErrInvalidData, query))
+		return
+	}
+
+	tx.Statement.AddClause(clause.With{Recursive: recursive, CTEs: []clause.CTE{cte}})
+	return
+}
 
 // Table specify the table you would like to run db operations
 func (db *DB) Table(name string, args ...interface{}) (tx *DB) {
@@ -226,10 +284,15 @@ func (db *DB) Having(query interface{}, args ...interface{}) (tx *DB) {
 //
 //	db.Order("name DESC")
 //	db.Order(clause.OrderByColumn{Column: clause.Column{Name: "name"}, Desc: true})
+//	db.Order(clause.Expr{SQL: "FIELD(id,?)", Vars: []interface{}{[]int{3, 1, 2}}, WithoutParentheses: true})
 func (db *DB) Order(value interface{}) (tx *DB) {
 	tx = db.getInstance()
 
 	switch v := value.(type) {
+	case clause.OrderBy:
+		tx.Statement.AddClause(v)
+	case clause.Expression:
+		tx.Statement.AddClause(clause.OrderBy{Expression: v})
 	case clause.OrderByColumn:
 		tx.Statement.AddClause(clause.OrderBy{
 			Columns: []clause.OrderByColumn{v},
@@ -279,6 +342,19 @@ func (db *DB) Scopes(funcs ...func(*DB) *DB) (tx *DB) {
 	return tx
 }
 
+// executeScopes applies scopes of db's statement, including scopes added by them
+func (db *DB) executeScopes() (tx *DB) {
+	tx = db
+	for len(tx.Statement.scopes) > 0 {
+		scopes := tx.Statement.scopes
+		tx.Statement.scopes = nil
+		for _, scope := range scopes {
+			tx = scope(tx)
+		}
+	}
+	return tx
+}
+
 // Preload preload associations with given conditions
 //
 //	db.Preload("Orders", "state NOT IN (?)", "cancelled").Find(&users)
@@ -143,6 +143,31 @@ func (or OrConditions) Build(builder Builder) {
 	}
 }
 
+// Keyset returns the condition of rows after values of ordered columns, e.g: (a > 1 OR (a = 1 AND b > 2)), columns in
+// descending order compare with <. The condition is a single expression ANDed with other conditions
+func Keyset(columns []OrderByColumn, values []interface{}) Expression {
+	ors := make([]Expression, 0, len(columns))
+	for i, column := range columns {
+		ands := make([]Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, Eq{Column: columns[j].Column, Value: values[j]})
+		}
+
+		if column.Desc {
+			ands = append(ands, Lt{Column: column.Column, Value: values[i]})
+		} else {
+			ands = append(ands, Gt{Column: column.Column, Value: values[i]})
+		}
+		ors = append(ors, And(ands...))
+	}
+
+	// a single OR condition would be ORed with other conditions
+	if len(ors) == 1 {
+		return ors[0]
+	}
+	return Or(ors...)
+}
+
 func Not(exprs ...Expression) Expression {
 	if len(exprs) == 0 {
 		return nil
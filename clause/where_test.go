@@ -113,3 +113,40 @@ func TestWhere(t *testing.T) {
 		})
 	}
 }
+
+func TestKeyset(t *testing.T) {
+	name, age := clause.Column{Name: "name"}, clause.Column{Name: "age"}
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Where{
+				Exprs: []clause.Expression{clause.Keyset([]clause.OrderByColumn{{Column: clause.PrimaryColumn}}, []interface{}{1})},
+			}},
+			"SELECT * FROM `users` WHERE `users`.`id` > ?",
+			[]interface{}{1},
+		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Where{
+				Exprs: []clause.Expression{clause.Eq{Column: age, Value: 18}, clause.Keyset([]clause.OrderByColumn{{Column: clause.PrimaryColumn, Desc: true}}, []interface{}{1})},
+			}},
+			"SELECT * FROM `users` WHERE `age` = ? AND `users`.`id` < ?",
+			[]interface{}{18, 1},
+		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Where{
+				Exprs: []clause.Expression{clause.Eq{Column: age, Value: 18}, clause.Keyset([]clause.OrderByColumn{{Column: name, Desc: true}, {Column: clause.PrimaryColumn}}, []interface{}{"jinzhu", 1})},
+			}},
+			"SELECT * FROM `users` WHERE `age` = ? AND (`name` < ? OR (`name` = ? AND `users`.`id` > ?))",
+			[]interface{}{18, "jinzhu", "jinzhu", 1},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
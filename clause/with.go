@@ -1,3 +1,62 @@
 package clause
 
-type With struct{}
+// With with clause, renders common table expressions
+//
+//	WITH RECURSIVE `tree` (`id`,`parent_id`) AS (SELECT ...),`leaves` AS (SELECT ...)
+type With struct {
+	Recursive bool
+	CTEs      []CTE
+}
+
+// CTE common table expression, Query could be a `*database.DB` subquery or an Expression
+type CTE struct {
+	Name    string
+	Columns []string
+	Query   interface{}
+}
+
+// Name with clause name
+func (with With) Name() string {
+	return "WITH"
+}
+
+// Build build with clause
+func (with With) Build(builder Builder) {
+	if with.Recursive {
+		builder.WriteString("RECURSIVE ")
+	}
+
+	for idx, cte := range with.CTEs {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		cte.Build(builder)
+	}
+}
+
+// MergeClause merge with clauses
+func (with With) MergeClause(clause *Clause) {
+	if v, ok := clause.Expression.(With); ok {
+		ctes := make([]CTE, len(v.CTEs), len(v.CTEs)+len(with.CTEs))
+		copy(ctes, v.CTEs)
+		with.CTEs = append(ctes, with.CTEs...)
+		with.Recursive = with.Recursive || v.Recursive
+	}
+
+	clause.Expression = with
+}
+
+// Build build common table expression
+func (cte CTE) Build(builder Builder) {
+	builder.WriteQuoted(cte.Name)
+	if len(cte.Columns) > 0 {
+		builder.WriteByte(' ')
+		builder.WriteQuoted(cte.Columns)
+	}
+
+	builder.WriteString(" AS (")
+	if cte.Query != nil {
+		builder.AddVar(builder, cte.Query)
+	}
+	builder.WriteByte(')')
+}
@@ -0,0 +1,53 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/driver005/database/clause"
+)
+
+func TestWith(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.With{CTEs: []clause.CTE{{
+				Name:  "active_users",
+				Query: clause.Expr{SQL: "SELECT * FROM `users` WHERE `active` = ?", Vars: []interface{}{true}},
+			}}}, clause.Select{}, clause.From{Tables: []clause.Table{{Name: "active_users"}}}},
+			"WITH `active_users` AS (SELECT * FROM `users` WHERE `active` = ?) SELECT * FROM `active_users`",
+			[]interface{}{true},
+		},
+		{
+			[]clause.Interface{clause.With{Recursive: true, CTEs: []clause.CTE{{
+				Name:    "tree",
+				Columns: []string{"id", "parent_id"},
+				Query:   clause.Expr{SQL: "SELECT id, parent_id FROM nodes WHERE id = ? UNION ALL SELECT nodes.id, nodes.parent_id FROM nodes JOIN tree ON nodes.parent_id = tree.id", Vars: []interface{}{1}},
+			}}}, clause.Select{}, clause.From{Tables: []clause.Table{{Name: "tree"}}}},
+			"WITH RECURSIVE `tree` (`id`,`parent_id`) AS (SELECT id, parent_id FROM nodes WHERE id = ? UNION ALL SELECT nodes.id, nodes.parent_id FROM nodes JOIN tree ON nodes.parent_id = tree.id) SELECT * FROM `tree`",
+			[]interface{}{1},
+		},
+		{
+			[]clause.Interface{clause.With{CTEs: []clause.CTE{{
+				Name:  "a",
+				Query: clause.Expr{SQL: "SELECT ?", Vars: []interface{}{1}},
+			}}}, clause.With{Recursive: true, CTEs: []clause.CTE{{
+				Name:  "b",
+				Query: clause.Expr{SQL: "SELECT ?", Vars: []interface{}{2}},
+			}}}, clause.Select{}, clause.From{}, clause.Where{
+				Exprs: []clause.Expression{clause.Eq{Column: clause.PrimaryColumn, Value: 3}},
+			}},
+			"WITH RECURSIVE `a` AS (SELECT ?),`b` AS (SELECT ?) SELECT * FROM `users` WHERE `users`.`id` = ?",
+			[]interface{}{1, 2, 3},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package datatypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/driver005/database/schema"
+)
+
+// JSON raw JSON document, migrated to the JSON column type of dialects, e.g: jsonb of postgres
+//
+//	type User struct {
+//		Settings datatypes.JSON
+//	}
+//
+//	db.Create(&User{Settings: datatypes.JSON(`{"theme": "dark"}`)})
+type JSON json.RawMessage
+
+func (JSON) DBDataType() string {
+	return string(schema.JSON)
+}
+
+// Scan implements sql.Scanner interface
+func (j *JSON) Scan(value interface{}) error {
+	bytes, err := bytesOf(value)
+	if err != nil || bytes == nil {
+		*j = nil
+		return err
+	}
+
+	*j = append((*j)[0:0], bytes...)
+	return nil
+}
+
+// Value implements driver.Valuer interface
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+// MarshalJSON returns j as the JSON encoding of j
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if j == nil {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON sets *j to a copy of data
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	if j == nil {
+		return errors.New("datatypes.JSON: UnmarshalJSON on nil pointer")
+	}
+	*j = append((*j)[0:0], data...)
+	return nil
+}
+
+func (j JSON) String() string {
+	return string(j)
+}
+
+// JSONMap JSON object of string keys, migrated to the JSON column type of dialects
+type JSONMap map[string]interface{}
+
+func (JSONMap) DBDataType() string {
+	return string(schema.JSON)
+}
+
+// Scan implements sql.Scanner interface
+func (m *JSONMap) Scan(value interface{}) error {
+	bytes, err := bytesOf(value)
+	if err != nil || bytes == nil {
+		*m = nil
+		return err
+	}
+
+	result := JSONMap{}
+	if err = json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+	*m = result
+	return nil
+}
+
+// Value implements driver.Valuer interface
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	bytes, err := json.Marshal(map[string]interface{}(m))
+	return string(bytes), err
+}
+
+func bytesOf(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("failed to unmarshal JSON value: %#v", value)
+}
@@ -0,0 +1,314 @@
+package datatypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+)
+
+type jsonOperator int
+
+const (
+	jsonExtract jsonOperator = iota
+	jsonHasKey
+	jsonEquals
+	jsonContains
+)
+
+// JSONQueryExpression query of JSON columns, built to the JSON functions or operators of the statement's dialect,
+// paths are dot separated keys, keys of digits are indexes of arrays, e.g: settings.theme, tags.0
+type JSONQueryExpression struct {
+	column   string
+	operator jsonOperator
+	keys     []string
+	value    interface{}
+}
+
+// JSONQuery returns the query expression of column
+//
+//	db.Where(datatypes.JSONQuery("settings").HasKey("theme")).Find(&users)
+//	db.Where(datatypes.JSONQuery("settings").Equals("theme", "dark")).Find(&users)
+//	db.Where(datatypes.JSONQuery("metadata").Contains(map[string]interface{}{"tags": []string{"go"}})).Find(&users)
+//	db.Order(datatypes.JSONQuery("metadata").Extract("priority")).Find(&users)
+func JSONQuery(column string) JSONQueryExpression {
+	return JSONQueryExpression{column: column}
+}
+
+// Extract the value of path, e.g: order by values of JSON documents
+func (q JSONQueryExpression) Extract(path string) JSONQueryExpression {
+	q.operator, q.keys = jsonExtract, jsonKeys(path)
+	return q
+}
+
+// HasKey the JSON document has path, values of JSON null included
+func (q JSONQueryExpression) HasKey(path string) JSONQueryExpression {
+	q.operator, q.keys = jsonHasKey, jsonKeys(path)
+	return q
+}
+
+// Equals the value of path equals value
+func (q JSONQueryExpression) Equals(path string, value interface{}) JSONQueryExpression {
+	q.operator, q.keys, q.value = jsonEquals, jsonKeys(path), value
+	return q
+}
+
+// Contains the JSON document contains value, e.g: objects of a subset of keys, arrays of a subset of elements,
+// supported by mysql and postgres only
+func (q JSONQueryExpression) Contains(value interface{}) JSONQueryExpression {
+	q.operator, q.value = jsonContains, value
+	return q
+}
+
+// Build implements clause.Expression interface
+func (q JSONQueryExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*database.Statement)
+	if !ok {
+		return
+	}
+
+	var (
+		column = clause.Column{Name: q.column}
+		path   = jsonPath(q.keys)
+		value  string
+		err    error
+	)
+
+	if q.operator == jsonEquals || q.operator == jsonContains {
+		if value, err = jsonValue(q.value); err != nil {
+			stmt.AddError(err)
+			return
+		}
+	}
+
+	switch name := stmt.Dialector.Name(); name {
+	case "mysql":
+		switch q.operator {
+		case jsonExtract:
+			build(stmt, "JSON_EXTRACT(?, ?)", column, path)
+		case jsonHasKey:
+			build(stmt, "JSON_CONTAINS_PATH(?, 'one', ?)", column, path)
+		case jsonEquals:
+			build(stmt, "JSON_EXTRACT(?, ?) = CAST(? AS JSON)", column, path, value)
+		case jsonContains:
+			build(stmt, "JSON_CONTAINS(?, ?)", column, value)
+		}
+	case "postgres":
+		switch q.operator {
+		case jsonExtract:
+			build(stmt, "? #> "+postgresPath(q.keys), append([]interface{}{column}, keyVars(q.keys)...)...)
+		case jsonHasKey:
+			build(stmt, "? #> "+postgresPath(q.keys)+" IS NOT NULL", append([]interface{}{column}, keyVars(q.keys)...)...)
+		case jsonEquals:
+			build(stmt, "? #> "+postgresPath(q.keys)+" = CAST(? AS jsonb)", append(append([]interface{}{column}, keyVars(q.keys)...), value)...)
+		case jsonContains:
+			build(stmt, "? @> CAST(? AS jsonb)", column, value)
+		}
+	case "sqlite":
+		switch q.operator {
+		case jsonExtract:
+			build(stmt, "json_extract(?, ?)", column, path)
+		case jsonHasKey:
+			build(stmt, "json_type(?, ?) IS NOT NULL", column, path)
+		case jsonEquals:
+			// json_extract converts JSON values to SQL values, e.g: true to 1, so does extracting the root of value
+			build(stmt, "json_extract(?, ?) = json_extract(?, '$')", column, path, value)
+		case jsonContains:
+			stmt.AddError(fmt.Errorf("%w: JSON contains isn't supported by %s", database.ErrUnsupportedDriver, name))
+		}
+	case "sqlserver":
+		switch q.operator {
+		case jsonExtract:
+			build(stmt, "JSON_VALUE(?, ?)", column, path)
+		case jsonHasKey:
+			build(stmt, "JSON_PATH_EXISTS(?, ?) = 1", column, path)
+		case jsonEquals:
+			// JSON_VALUE returns scalars as text, objects and arrays are returned by JSON_QUERY
+			if isScalar(q.value) {
+				build(stmt, "JSON_VALUE(?, ?) = ?", column, path, scalarText(value))
+			} else {
+				build(stmt, "JSON_QUERY(?, ?) = ?", column, path, value)
+			}
+		case jsonContains:
+			stmt.AddError(fmt.Errorf("%w: JSON contains isn't supported by %s", database.ErrUnsupportedDriver, name))
+		}
+	default:
+		stmt.AddError(fmt.Errorf("%w: JSON queries aren't supported by %s", database.ErrUnsupportedDriver, name))
+	}
+}
+
+// JSONSetExpression update of JSON columns, sets values of paths, columns of NULL are updated as empty objects
+type JSONSetExpression struct {
+	column string
+	keys   [][]string
+	values []interface{}
+}
+
+// JSONSet returns the update expression of column
+//
+//	db.Model(&user).Update("settings", datatypes.JSONSet("settings").Set("theme", "dark").Set("notify.email", false))
+func JSONSet(column string) JSONSetExpression {
+	return JSONSetExpression{column: column}
+}
+
+// Set sets the value of path, parents of path aren't created, nil values delete path on sqlserver
+func (s JSONSetExpression) Set(path string, value interface{}) JSONSetExpression {
+	s.keys = append(s.keys[:len(s.keys):len(s.keys)], jsonKeys(path))
+	s.values = append(s.values[:len(s.values):len(s.values)], value)
+	return s
+}
+
+// Build implements clause.Expression interface
+func (s JSONSetExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*database.Statement)
+	if !ok {
+		return
+	}
+
+	var (
+		column = clause.Column{Name: s.column}
+		values = make([]string, len(s.values))
+		err    error
+	)
+
+	for idx, value := range s.values {
+		if values[idx], err = jsonValue(value); err != nil {
+			stmt.AddError(err)
+			return
+		}
+	}
+
+	switch name := stmt.Dialector.Name(); name {
+	case "mysql":
+		build(stmt, "JSON_SET(COALESCE(?, JSON_OBJECT())", column)
+		for idx, keys := range s.keys {
+			build(stmt, ", ?, CAST(? AS JSON)", jsonPath(keys), values[idx])
+		}
+		stmt.WriteByte(')')
+	case "postgres":
+		stmt.WriteString(strings.Repeat("jsonb_set(", len(s.keys)))
+		build(stmt, "COALESCE(?, '{}')", column)
+		for idx, keys := range s.keys {
+			build(stmt, ", "+postgresPath(keys)+", CAST(? AS jsonb))", append(keyVars(keys), values[idx])...)
+		}
+	case "sqlite":
+		build(stmt, "json_set(COALESCE(?, '{}')", column)
+		for idx, keys := range s.keys {
+			build(stmt, ", ?, json(?)", jsonPath(keys), values[idx])
+		}
+		stmt.WriteByte(')')
+	case "sqlserver":
+		stmt.WriteString(strings.Repeat("JSON_MODIFY(", len(s.keys)))
+		build(stmt, "COALESCE(?, '{}')", column)
+		for idx, keys := range s.keys {
+			// JSON_MODIFY sets text as JSON strings and bits as booleans, objects and arrays are set with JSON_QUERY
+			switch value := values[idx]; {
+			case !isScalar(s.values[idx]):
+				build(stmt, ", ?, JSON_QUERY(?))", jsonPath(keys), value)
+			case value == "true":
+				build(stmt, ", ?, CAST(1 AS BIT))", jsonPath(keys))
+			case value == "false":
+				build(stmt, ", ?, CAST(0 AS BIT))", jsonPath(keys))
+			case strings.HasPrefix(value, `"`):
+				build(stmt, ", ?, ?)", jsonPath(keys), scalarText(value))
+			default:
+				build(stmt, ", ?, ?)", jsonPath(keys), s.values[idx])
+			}
+		}
+	default:
+		stmt.AddError(fmt.Errorf("%w: JSON updates aren't supported by %s", database.ErrUnsupportedDriver, name))
+	}
+}
+
+func build(stmt *database.Statement, sql string, vars ...interface{}) {
+	clause.Expr{SQL: sql, Vars: vars}.Build(stmt)
+}
+
+// jsonKeys splits path to keys, e.g: settings.theme to [settings, theme]
+func jsonKeys(path string) []string {
+	return strings.Split(path, ".")
+}
+
+var (
+	indexRegexp = regexp.MustCompile(`^[0-9]+$`)
+	keyRegexp   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// jsonPath returns the SQL/JSON path of keys used by mysql, sqlite and sqlserver, e.g: $.settings.theme, $.tags[0]
+func jsonPath(keys []string) string {
+	var path strings.Builder
+	path.WriteByte('$')
+	for _, key := range keys {
+		switch {
+		case indexRegexp.MatchString(key):
+			path.WriteString("[" + key + "]")
+		case keyRegexp.MatchString(key):
+			path.WriteString("." + key)
+		default:
+			path.WriteString(`."` + strings.ReplaceAll(strings.ReplaceAll(key, `\`, `\\`), `"`, `\"`) + `"`)
+		}
+	}
+	return path.String()
+}
+
+// postgresPath returns the text array of keys, e.g: ARRAY[?,?], keys are vars, indexes of arrays included
+func postgresPath(keys []string) string {
+	return "ARRAY[" + strings.TrimSuffix(strings.Repeat("?,", len(keys)), ",") + "]"
+}
+
+func keyVars(keys []string) []interface{} {
+	vars := make([]interface{}, len(keys))
+	for idx, key := range keys {
+		vars[idx] = key
+	}
+	return vars
+}
+
+// jsonValue returns the JSON encoding of value, JSON and json.RawMessage are JSON already
+func jsonValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case JSON:
+		if len(v) == 0 {
+			return "null", nil
+		}
+		return string(v), nil
+	case json.RawMessage:
+		if len(v) == 0 {
+			return "null", nil
+		}
+		return string(v), nil
+	}
+
+	bytes, err := json.Marshal(value)
+	return string(bytes), err
+}
+
+// isScalar returns true if value is encoded to a JSON string, number, boolean or null
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case JSON, JSONMap, json.RawMessage:
+		return false
+	case time.Time, *time.Time:
+		return true
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return false
+	}
+	return true
+}
+
+// scalarText returns the text of scalar JSON values, e.g: strings without quotes
+func scalarText(value string) string {
+	var str string
+	if err := json.Unmarshal([]byte(value), &str); err == nil {
+		return str
+	}
+	return value
+}
@@ -0,0 +1,158 @@
+package datatypes_test
+
+import (
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/datatypes"
+	"github.com/driver005/database/driver/mysql"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/driver/sqlserver"
+	"github.com/driver005/database/logger"
+)
+
+type User struct {
+	ID       uint
+	Settings datatypes.JSON
+}
+
+// dryRunDBs opens databases of all dialects in DryRun mode
+func dryRunDBs(t *testing.T) map[string]*database.DB {
+	dialectors := map[string]database.Dialector{
+		"mysql":     mysql.New(mysql.Config{DSN: "user:pass@tcp(localhost:3306)/database", SkipInitializeWithVersion: true}),
+		"postgres":  postgres.Open("host=localhost"),
+		"sqlite":    sqlite.Open(":memory:"),
+		"sqlserver": sqlserver.Open("sqlserver://localhost"),
+	}
+
+	dbs := map[string]*database.DB{}
+	for name, dialector := range dialectors {
+		db, err := database.Open(dialector, &database.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: logger.Discard})
+		if err != nil {
+			t.Fatalf("failed to open %v, got error %v", name, err)
+		}
+		dbs[name] = db
+	}
+	return dbs
+}
+
+func explain(db *database.DB) string {
+	return db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...)
+}
+
+func TestJSONQuery(t *testing.T) {
+	dbs := dryRunDBs(t)
+
+	results := []struct {
+		Name string
+		Expr datatypes.JSONQueryExpression
+		SQLs map[string]string
+	}{
+		{
+			"HasKey", datatypes.JSONQuery("settings").HasKey("notify.email"),
+			map[string]string{
+				"mysql":     "JSON_CONTAINS_PATH(`settings`, 'one', '$.notify.email')",
+				"postgres":  `"settings" #> ARRAY['notify','email'] IS NOT NULL`,
+				"sqlite":    "json_type(`settings`, \"$.notify.email\") IS NOT NULL",
+				"sqlserver": "JSON_PATH_EXISTS([settings], '$.notify.email') = 1",
+			},
+		},
+		{
+			"Equals", datatypes.JSONQuery("settings").Equals("tags.0", "go"),
+			map[string]string{
+				"mysql":     "JSON_EXTRACT(`settings`, '$.tags[0]') = CAST('\"go\"' AS JSON)",
+				"postgres":  `"settings" #> ARRAY['tags','0'] = CAST('"go"' AS jsonb)`,
+				"sqlite":    "json_extract(`settings`, \"$.tags[0]\") = json_extract(\"\\\"go\\\"\", '$')",
+				"sqlserver": "JSON_VALUE([settings], '$.tags[0]') = 'go'",
+			},
+		},
+		{
+			"EqualsObject", datatypes.JSONQuery("settings").Equals("notify", map[string]bool{"email": true}),
+			map[string]string{
+				"mysql":     "JSON_EXTRACT(`settings`, '$.notify') = CAST('{\"email\":true}' AS JSON)",
+				"postgres":  `"settings" #> ARRAY['notify'] = CAST('{"email":true}' AS jsonb)`,
+				"sqlite":    "json_extract(`settings`, \"$.notify\") = json_extract(\"{\\\"email\\\":true}\", '$')",
+				"sqlserver": "JSON_QUERY([settings], '$.notify') = '{\"email\":true}'",
+			},
+		},
+		{
+			"Contains", datatypes.JSONQuery("settings").Contains(map[string]interface{}{"tags": []string{"go"}}),
+			map[string]string{
+				"mysql":    "JSON_CONTAINS(`settings`, '{\"tags\":[\"go\"]}')",
+				"postgres": `"settings" @> CAST('{"tags":["go"]}' AS jsonb)`,
+			},
+		},
+	}
+
+	for _, result := range results {
+		for name, db := range dbs {
+			t.Run(result.Name+"/"+name, func(t *testing.T) {
+				tx := db.Where(result.Expr).Find(&[]User{})
+				expected, ok := result.SQLs[name]
+				if !ok {
+					if tx.Error == nil {
+						t.Errorf("unsupported queries should be rejected")
+					}
+					return
+				}
+
+				if tx.Error != nil {
+					t.Fatalf("no error should happen when build query, got %v", tx.Error)
+				}
+
+				if sql := explain(tx); sql != sqlPrefix(db)+expected {
+					t.Errorf("SQL expects %v got %v", sqlPrefix(db)+expected, sql)
+				}
+			})
+		}
+	}
+}
+
+func sqlPrefix(db *database.DB) string {
+	switch db.Dialector.Name() {
+	case "mysql", "sqlite":
+		return "SELECT * FROM `users` WHERE "
+	case "sqlserver":
+		return "SELECT * FROM [users] WHERE "
+	}
+	return `SELECT * FROM "users" WHERE `
+}
+
+func TestJSONExtract(t *testing.T) {
+	expects := map[string]string{
+		"mysql":     "SELECT * FROM `users` ORDER BY JSON_EXTRACT(`settings`, '$.priority')",
+		"postgres":  `SELECT * FROM "users" ORDER BY "settings" #> ARRAY['priority']`,
+		"sqlite":    "SELECT * FROM `users` ORDER BY json_extract(`settings`, \"$.priority\")",
+		"sqlserver": "SELECT * FROM [users] ORDER BY JSON_VALUE([settings], '$.priority')",
+	}
+
+	for name, db := range dryRunDBs(t) {
+		tx := db.Order(datatypes.JSONQuery("settings").Extract("priority")).Find(&[]User{})
+		if sql := explain(tx); tx.Error != nil || sql != expects[name] {
+			t.Errorf("%v: SQL expects %v got %v, error %v", name, expects[name], sql, tx.Error)
+		}
+	}
+}
+
+func TestJSONSet(t *testing.T) {
+	expects := map[string]string{
+		"mysql": "UPDATE `users` SET `settings`=JSON_SET(COALESCE(`settings`, JSON_OBJECT()), '$.theme', CAST('\"dark\"' AS JSON), " +
+			"'$.notify.email', CAST('false' AS JSON), '$.size', CAST('3' AS JSON), '$.tags', CAST('[\"go\"]' AS JSON)) WHERE `id` = 1",
+		"postgres": `UPDATE "users" SET "settings"=jsonb_set(jsonb_set(jsonb_set(jsonb_set(COALESCE("settings", '{}'), ` +
+			`ARRAY['theme'], CAST('"dark"' AS jsonb)), ARRAY['notify','email'], CAST('false' AS jsonb)), ` +
+			`ARRAY['size'], CAST('3' AS jsonb)), ARRAY['tags'], CAST('["go"]' AS jsonb)) WHERE "id" = 1`,
+		"sqlite": "UPDATE `users` SET `settings`=json_set(COALESCE(`settings`, '{}'), \"$.theme\", json(\"\\\"dark\\\"\"), " +
+			"\"$.notify.email\", json(\"false\"), \"$.size\", json(\"3\"), \"$.tags\", json(\"[\\\"go\\\"]\")) WHERE `id` = 1",
+		"sqlserver": "UPDATE [users] SET [settings]=JSON_MODIFY(JSON_MODIFY(JSON_MODIFY(JSON_MODIFY(COALESCE([settings], '{}'), " +
+			"'$.theme', 'dark'), '$.notify.email', CAST(0 AS BIT)), '$.size', 3), '$.tags', JSON_QUERY('[\"go\"]')) WHERE [id] = 1",
+	}
+
+	expr := datatypes.JSONSet("settings").Set("theme", "dark").Set("notify.email", false).Set("size", 3).Set("tags", []string{"go"})
+	for name, db := range dryRunDBs(t) {
+		tx := db.Model(&User{ID: 1}).Update("settings", expr)
+		if sql := explain(tx); tx.Error != nil || sql != expects[name] {
+			t.Errorf("%v: SQL expects %v got %v, error %v", name, expects[name], sql, tx.Error)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -25,6 +26,9 @@ type Config struct {
 	NamingStrategy schema.Namer
 	// FullSaveAssociations full save associations
 	FullSaveAssociations bool
+	// KeepJoinRowsOnSoftDelete keeps many2many join rows when records are soft deleted with their associations, so
+	// the associations come back when the records are restored
+	KeepJoinRowsOnSoftDelete bool
 	// Logger
 	Logger logger.Interface
 	// NowFunc the function to be used when creating a new timestamp
@@ -105,6 +109,7 @@ type Session struct {
 	DisableNestedTransaction bool
 	AllowGlobalUpdate        bool
 	FullSaveAssociations     bool
+	KeepJoinRowsOnSoftDelete bool
 	QueryFields              bool
 	Context                  context.Context
 	Logger                   logger.Interface
@@ -165,6 +170,10 @@ func Open(dialector Dialector, opts ...Option) (db *DB, err error) {
 		config.cacheStore = &sync.Map{}
 	}
 
+	if serializer, ok := config.Dialector.(DataTypeSerializer); ok {
+		schema.SetDataTypeSerializer(config.cacheStore, serializer.DataTypeSerializer)
+	}
+
 	db = &DB{Config: config, clone: 1}
 
 	db.callbacks = initializeCallbacks(db)
@@ -236,6 +245,10 @@ func (db *DB) Session(config *Session) *DB {
 		txConfig.FullSaveAssociations = true
 	}
 
+	if config.KeepJoinRowsOnSoftDelete {
+		txConfig.KeepJoinRowsOnSoftDelete = true
+	}
+
 	if config.Context != nil || config.PrepareStmt || config.SkipHooks {
 		tx.Statement = tx.Statement.clone()
 		tx.Statement.DB = tx
@@ -343,8 +356,9 @@ func (db *DB) Callback() *callbacks {
 	return db.callbacks
 }
 
-// AddError add error to db
+// AddError add error to db, driver errors are translated if the dialector implements ErrorTranslator
 func (db *DB) AddError(err error) error {
+	err = db.translateError(err)
 	if db.Error == nil {
 		db.Error = err
 	} else if err != nil {
@@ -353,6 +367,16 @@ func (db *DB) AddError(err error) error {
 	return db.Error
 }
 
+func (db *DB) translateError(err error) error {
+	if translator, ok := db.Dialector.(ErrorTranslator); ok && err != nil {
+		var translated *TranslatedError
+		if !errors.As(err, &translated) {
+			return translator.Translate(err)
+		}
+	}
+	return err
+}
+
 // DB returns `*sql.DB`
 func (db *DB) DB() (*sql.DB, error) {
 	connPool := db.ConnPool
@@ -381,6 +405,11 @@ func (db *DB) getInstance() *DB {
 				Clauses:  map[string]clause.Clause{},
 				Vars:     make([]interface{}, 0, 8),
 			}
+
+			// new statements of a transaction share its hooks
+			if hooks, ok := db.Statement.Settings.Load(TxHooksKey); ok {
+				tx.Statement.Settings.Store(TxHooksKey, hooks)
+			}
 		} else {
 			// with clone statement
 			tx.Statement = db.Statement.clone()
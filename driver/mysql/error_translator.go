@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/driver005/database"
+	"github.com/go-sql-driver/mysql"
+)
+
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+var errCodes = map[uint16]error{
+	1062: database.ErrDuplicatedKey,
+	1451: database.ErrForeignKeyViolated,
+	1452: database.ErrForeignKeyViolated,
+	3819: database.ErrCheckConstraintViolated,
+	1048: database.ErrNotNullViolated,
+	1213: database.ErrDeadlock,
+	1205: database.ErrLockTimeout,
+}
+
+var (
+	// Duplicate entry 'jinzhu' for key 'users.idx_users_name'
+	duplicatedKeyMatcher = regexp.MustCompile("for key '(?:(\\w+)\\.)?([^']+)'")
+	// a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_users_orders` FOREIGN KEY ...
+	foreignKeyMatcher = regexp.MustCompile("\\(`[^`]+`\\.`([^`]+)`, CONSTRAINT `([^`]+)`")
+	// Check constraint 'chk_users_age' is violated.
+	checkConstraintMatcher = regexp.MustCompile("constraint '([^']+)'")
+)
+
+// Translate translate mysql.MySQLError to portable errors
+func (dialector Dialector) Translate(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+
+	translatedErr, ok := errCodes[mysqlErr.Number]
+	if !ok {
+		return err
+	}
+
+	result := &database.TranslatedError{Err: translatedErr, Cause: err}
+	switch translatedErr {
+	case database.ErrDuplicatedKey:
+		if matches := duplicatedKeyMatcher.FindStringSubmatch(mysqlErr.Message); len(matches) == 3 {
+			result.Table, result.Constraint = matches[1], matches[2]
+		}
+	case database.ErrForeignKeyViolated:
+		if matches := foreignKeyMatcher.FindStringSubmatch(mysqlErr.Message); len(matches) == 3 {
+			result.Table, result.Constraint = matches[1], matches[2]
+		}
+	case database.ErrCheckConstraintViolated:
+		if matches := checkConstraintMatcher.FindStringSubmatch(mysqlErr.Message); len(matches) == 2 {
+			result.Constraint = matches[1]
+		}
+	}
+	return result
+}
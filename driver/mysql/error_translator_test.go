@@ -0,0 +1,74 @@
+package mysql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/mysql"
+	driver "github.com/go-sql-driver/mysql"
+)
+
+func TestTranslate(t *testing.T) {
+	results := []struct {
+		Name       string
+		Err        *driver.MySQLError
+		Translated error
+		Table      string
+		Constraint string
+	}{
+		{
+			"DuplicatedKey",
+			&driver.MySQLError{Number: 1062, Message: "Duplicate entry 'jinzhu' for key 'users.idx_users_name'"},
+			database.ErrDuplicatedKey, "users", "idx_users_name",
+		},
+		{
+			"DuplicatedPrimaryKey",
+			&driver.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"},
+			database.ErrDuplicatedKey, "", "PRIMARY",
+		},
+		{
+			"ForeignKeyViolated",
+			&driver.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_users_orders` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"},
+			database.ErrForeignKeyViolated, "orders", "fk_users_orders",
+		},
+		{
+			"ForeignKeyReferenced",
+			&driver.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_users_orders` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"},
+			database.ErrForeignKeyViolated, "orders", "fk_users_orders",
+		},
+		{
+			"CheckConstraintViolated",
+			&driver.MySQLError{Number: 3819, Message: "Check constraint 'chk_users_age' is violated."},
+			database.ErrCheckConstraintViolated, "", "chk_users_age",
+		},
+		{"NotNullViolated", &driver.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"}, database.ErrNotNullViolated, "", ""},
+		{"Deadlock", &driver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}, database.ErrDeadlock, "", ""},
+		{"LockTimeout", &driver.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, database.ErrLockTimeout, "", ""},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			err := mysql.Dialector{}.Translate(result.Err)
+			if !errors.Is(err, result.Translated) {
+				t.Fatalf("expects %v, got %v", result.Translated, err)
+			}
+
+			var translated *database.TranslatedError
+			if !errors.As(err, &translated) || translated.Table != result.Table || translated.Constraint != result.Constraint {
+				t.Errorf("expects table %v constraint %v, got %+v", result.Table, result.Constraint, translated)
+			}
+
+			var cause *driver.MySQLError
+			if !errors.As(err, &cause) || cause != result.Err {
+				t.Errorf("driver error should be reachable, got %v", cause)
+			}
+		})
+	}
+
+	for _, err := range []error{errors.New("unknown"), &driver.MySQLError{Number: 1146}} {
+		if translated := (mysql.Dialector{}).Translate(err); translated != err {
+			t.Errorf("error %v shouldn't be translated, got %v", err, translated)
+		}
+	}
+}
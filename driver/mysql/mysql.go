@@ -42,14 +42,14 @@ type Dialector struct {
 }
 
 var (
-	// CreateClauses create clauses
+	// CreateClauses create clauses, MySQL doesn't accept a leading WITH for INSERT ... VALUES
 	CreateClauses = []string{"INSERT", "VALUES", "ON CONFLICT"}
 	// QueryClauses query clauses
 	QueryClauses = []string{}
 	// UpdateClauses update clauses
-	UpdateClauses = []string{"UPDATE", "SET", "WHERE", "ORDER BY", "LIMIT"}
+	UpdateClauses = []string{"WITH", "UPDATE", "SET", "WHERE", "ORDER BY", "LIMIT"}
 	// DeleteClauses delete clauses
-	DeleteClauses = []string{"DELETE", "FROM", "WHERE", "ORDER BY", "LIMIT"}
+	DeleteClauses = []string{"WITH", "DELETE", "FROM", "WHERE", "ORDER BY", "LIMIT"}
 
 	defaultDatetimePrecision = 3
 )
@@ -346,6 +346,8 @@ func (dialector Dialector) DataTypeOf(field *schema.Field) string {
 		return dialector.getSchemaTimeType(field)
 	case schema.Bytes:
 		return dialector.getSchemaBytesType(field)
+	case schema.JSON:
+		return "json"
 	default:
 		return dialector.getSchemaCustomType(field)
 	}
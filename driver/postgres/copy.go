@@ -0,0 +1,367 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/schema"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// CopyOptions options of CopyFrom
+type CopyOptions struct {
+	// Columns copied columns, defaults to creatable fields without database default values
+	Columns []string
+	// OnConflict copy into a temporary staging table and merge it into the table with
+	// INSERT ... SELECT ... ON CONFLICT, UpdateAll updates copied columns except primary keys
+	OnConflict *clause.OnConflict
+}
+
+// CopyFrom bulk loads values, a slice, array or channel of models, into the table of db's statement or the models
+// with the COPY protocol, values received from a channel are streamed until it's closed. db's connection pool has
+// to be a pgx backed *sql.DB, *sql.Conn or *Pool, or a transaction begun with Transaction, connections of
+// transactions begun with db.Begin or db.Transaction can't be accessed
+//
+//	rows, err := postgres.CopyFrom(db.WithContext(ctx), users, postgres.CopyOptions{
+//		OnConflict: &clause.OnConflict{Columns: []clause.Column{{Name: "email"}}, UpdateAll: true},
+//	})
+func CopyFrom(db *database.DB, values interface{}, opts ...CopyOptions) (rowsAffected int64, err error) {
+	var (
+		opt     CopyOptions
+		stmt    = db.Session(&database.Session{NewDB: true}).Statement
+		ctx     = db.Statement.Context
+		table   = db.Statement.Table
+		begin   = time.Now()
+		source  = &copySource{ctx: ctx, reflectValue: reflect.Indirect(reflect.ValueOf(values)), now: db.NowFunc()}
+		copySQL string
+	)
+
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	switch source.reflectValue.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Chan:
+	default:
+		return 0, fmt.Errorf("%w: CopyFrom requires a slice, array or channel of models, got %T", database.ErrInvalidData, values)
+	}
+
+	modelType := source.reflectValue.Type().Elem()
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	if err = stmt.Parse(reflect.New(modelType).Interface()); err != nil {
+		return 0, err
+	}
+	source.schema = stmt.Schema
+	if table == "" {
+		table = stmt.Schema.Table
+	}
+
+	if len(opt.Columns) > 0 {
+		for _, column := range opt.Columns {
+			field := stmt.Schema.LookUpField(column)
+			if field == nil || field.DBName == "" {
+				return 0, fmt.Errorf("%w: %s", database.ErrInvalidField, column)
+			}
+			source.fields = append(source.fields, field)
+		}
+	} else {
+		for _, dbName := range stmt.Schema.DBNames {
+			if field := stmt.Schema.FieldsByDBName[dbName]; field.Creatable && (!field.HasDefaultValue || field.DefaultValueInterface != nil) {
+				source.fields = append(source.fields, field)
+			}
+		}
+	}
+
+	columns := make([]string, len(source.fields))
+	for idx, field := range source.fields {
+		columns[idx] = field.DBName
+	}
+
+	defer func() {
+		db.Logger.Trace(ctx, begin, func() (string, int64) {
+			return copySQL, rowsAffected
+		}, err)
+	}()
+
+	err = rawConn(ctx, db.Statement.ConnPool, func(conn *pgx.Conn) error {
+		if opt.OnConflict == nil {
+			copySQL = fmt.Sprintf("COPY %s (%s) FROM STDIN", stmt.Quote(table), stmt.Quote(columns))
+			rowsAffected, err = conn.CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), columns, source)
+			return err
+		}
+
+		// merge in the transaction copying joins or a transaction of its own
+		var tx copyConn = conn
+		if conn.PgConn().TxStatus() == 'I' {
+			pgxTx, err := conn.Begin(ctx)
+			if err != nil {
+				return err
+			}
+			defer pgxTx.Rollback(ctx)
+			tx = pgxTx
+		}
+
+		staging := fmt.Sprintf("%s_staging_%d", stmt.Schema.Table, time.Now().UnixNano())
+		if _, err := tx.Exec(ctx, fmt.Sprintf(
+			"CREATE TEMPORARY TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP", stmt.Quote(staging), stmt.Quote(table),
+		)); err != nil {
+			return err
+		}
+
+		copySQL = fmt.Sprintf("COPY %s (%s) FROM STDIN", stmt.Quote(staging), stmt.Quote(columns))
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{staging}, columns, source); err != nil {
+			return err
+		}
+
+		mergeSQL, vars := buildCopyMerge(stmt, table, staging, source.fields, *opt.OnConflict)
+		copySQL += "; " + db.Dialector.Explain(mergeSQL, vars...)
+		result, err := tx.Exec(ctx, mergeSQL, vars...)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected = result.RowsAffected()
+		if pgxTx, ok := tx.(pgx.Tx); ok {
+			return pgxTx.Commit(ctx)
+		}
+		return nil
+	})
+	return rowsAffected, err
+}
+
+// buildCopyMerge builds INSERT INTO table SELECT ... FROM staging ON CONFLICT ...
+func buildCopyMerge(stmt *database.Statement, table, staging string, fields []*schema.Field, onConflict clause.OnConflict) (string, []interface{}) {
+	columns := make([]clause.Column, len(fields))
+	for idx, field := range fields {
+		columns[idx] = clause.Column{Name: field.DBName}
+	}
+
+	if onConflict.UpdateAll {
+		for _, field := range fields {
+			if !field.PrimaryKey && field.AutoCreateTime == 0 {
+				onConflict.DoUpdates = append(onConflict.DoUpdates, clause.AssignmentColumns([]string{field.DBName})...)
+			}
+		}
+	}
+
+	if len(onConflict.Columns) == 0 && onConflict.OnConstraint == "" {
+		for _, field := range stmt.Schema.PrimaryFields {
+			onConflict.Columns = append(onConflict.Columns, clause.Column{Name: field.DBName})
+		}
+	}
+
+	if len(onConflict.DoUpdates) == 0 {
+		onConflict.DoNothing = true
+	}
+
+	mergeStmt := &database.Statement{DB: stmt.DB, Clauses: map[string]clause.Clause{}}
+	mergeStmt.AddVar(mergeStmt, clause.Expr{SQL: "INSERT INTO ? ? SELECT ", Vars: []interface{}{clause.Table{Name: table}, columns}})
+	for idx, column := range columns {
+		if idx > 0 {
+			mergeStmt.WriteByte(',')
+		}
+		mergeStmt.WriteQuoted(column)
+	}
+	mergeStmt.AddVar(mergeStmt, clause.Expr{SQL: " FROM ? ON CONFLICT ", Vars: []interface{}{clause.Table{Name: staging}}})
+	onConflict.Build(mergeStmt)
+	return mergeStmt.SQL.String(), mergeStmt.Vars
+}
+
+// copyConn pgx connection or transaction copying
+type copyConn interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// Transaction runs fc in a transaction of db like db.Transaction, the transaction is begun on a dedicated connection,
+// so CopyFrom joins it
+//
+//	err := postgres.Transaction(db, func(tx *database.DB) error {
+//		if err := tx.Create(&batch).Error; err != nil {
+//			return err
+//		}
+//		_, err := postgres.CopyFrom(tx, users)
+//		return err
+//	})
+func Transaction(db *database.DB, fc func(tx *database.DB) error, opts ...*sql.TxOptions) error {
+	connPool := db.Statement.ConnPool
+	if preparedStmtDB, ok := connPool.(*database.PreparedStmtDB); ok {
+		connPool = preparedStmtDB.ConnPool
+	}
+
+	var sqlDB *sql.DB
+	switch pool := connPool.(type) {
+	case *connTx:
+		return db.Transaction(fc, opts...)
+	case *Pool:
+		sqlDB = pool.DB
+	case *sql.DB:
+		sqlDB = pool
+	default:
+		return fmt.Errorf("%w: Transaction requires a *sql.DB or *Pool, got %T", database.ErrUnsupportedDriver, connPool)
+	}
+
+	tx := db.Session(&database.Session{Context: db.Statement.Context})
+	tx.Statement.ConnPool = connBeginner{DB: sqlDB}
+	return tx.Transaction(fc, opts...)
+}
+
+// connBeginner begins transactions on dedicated connections of DB
+type connBeginner struct {
+	*sql.DB
+}
+
+func (b connBeginner) BeginTx(ctx context.Context, opts *sql.TxOptions) (database.ConnPool, error) {
+	conn, err := b.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &connTx{Tx: tx, conn: conn}, nil
+}
+
+// connTx transaction of conn, conn is returned to the pool when it's committed or rolled back
+type connTx struct {
+	*sql.Tx
+	conn *sql.Conn
+}
+
+func (tx *connTx) Commit() error {
+	defer tx.conn.Close()
+	return tx.Tx.Commit()
+}
+
+func (tx *connTx) Rollback() error {
+	defer tx.conn.Close()
+	return tx.Tx.Rollback()
+}
+
+// rawConn calls fc with the pgx connection of connPool
+func rawConn(ctx context.Context, connPool database.ConnPool, fc func(*pgx.Conn) error) error {
+	if preparedStmtDB, ok := connPool.(*database.PreparedStmtDB); ok {
+		connPool = preparedStmtDB.ConnPool
+	}
+
+	var conn *sql.Conn
+	switch pool := connPool.(type) {
+	case *Pool:
+		c, err := pool.pgxPool.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Release()
+		return fc(c.Conn())
+	case *sql.DB:
+		c, err := pool.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		conn = c
+	case *sql.Conn:
+		conn = pool
+	case *connTx:
+		conn = pool.conn
+	default:
+		return fmt.Errorf("%w: CopyFrom requires a *sql.DB, *sql.Conn, *Pool or a transaction of Transaction, got %T", database.ErrUnsupportedDriver, connPool)
+	}
+
+	return conn.Raw(func(driverConn interface{}) error {
+		if c, ok := driverConn.(*stdlib.Conn); ok {
+			return fc(c.Conn())
+		}
+		return fmt.Errorf("%w: CopyFrom requires pgx, got %T", database.ErrUnsupportedDriver, driverConn)
+	})
+}
+
+// copySource pgx.CopyFromSource of models
+type copySource struct {
+	ctx          context.Context
+	schema       *schema.Schema
+	fields       []*schema.Field
+	reflectValue reflect.Value
+	now          time.Time
+	index        int
+	current      reflect.Value
+	err          error
+}
+
+func (s *copySource) Next() bool {
+	if s.reflectValue.Kind() == reflect.Chan {
+		chosen, recv, ok := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.ctx.Done())},
+			{Dir: reflect.SelectRecv, Chan: s.reflectValue},
+		})
+
+		if chosen == 0 {
+			s.err = s.ctx.Err()
+			return false
+		}
+
+		if !ok {
+			return false
+		}
+		s.current = recv
+	} else {
+		if s.index >= s.reflectValue.Len() {
+			return false
+		}
+		s.current = s.reflectValue.Index(s.index)
+		s.index++
+	}
+
+	s.current = reflect.Indirect(s.current)
+	if !s.current.CanAddr() {
+		addressable := reflect.New(s.current.Type()).Elem()
+		addressable.Set(s.current)
+		s.current = addressable
+	}
+	return true
+}
+
+func (s *copySource) Values() ([]interface{}, error) {
+	values := make([]interface{}, len(s.fields))
+	for idx, field := range s.fields {
+		value, isZero := field.ValueOf(s.ctx, s.current)
+		if isZero {
+			if field.DefaultValueInterface != nil {
+				value = field.DefaultValueInterface
+			} else if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
+				if err := field.Set(s.ctx, s.current, s.now); err != nil {
+					return nil, err
+				}
+				value, _ = field.ValueOf(s.ctx, s.current)
+			}
+		}
+
+		if valuer, ok := value.(driver.Valuer); ok {
+			var err error
+			if value, err = valuer.Value(); err != nil {
+				return nil, err
+			}
+		}
+		values[idx] = value
+	}
+	return values, nil
+}
+
+func (s *copySource) Err() error {
+	return s.err
+}
@@ -0,0 +1,200 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/logger"
+)
+
+type Import struct {
+	ID        uint
+	Name      string
+	Tags      []string `database:"serializer:json"`
+	CreatedAt time.Time
+}
+
+type connPool struct {
+	database.ConnPool
+}
+
+func TestCopyFromErrors(t *testing.T) {
+	sqlDB, err := sql.Open(sqlite.DriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite, got error %v", err)
+	}
+	defer sqlDB.Close()
+
+	open := func(conn database.ConnPool) *database.DB {
+		db, err := database.Open(postgres.New(postgres.Config{Conn: conn}), &database.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+		if err != nil {
+			t.Fatalf("failed to open database, got error %v", err)
+		}
+		return db
+	}
+
+	results := []struct {
+		Name   string
+		DB     *database.DB
+		Values interface{}
+		Opts   []postgres.CopyOptions
+		Err    error
+	}{
+		{"NotSlice", open(sqlDB), &Import{}, nil, database.ErrInvalidData},
+		{"UnknownColumn", open(sqlDB), []Import{{}}, []postgres.CopyOptions{{Columns: []string{"unknown"}}}, database.ErrInvalidField},
+		{"NotPgx", open(sqlDB), []Import{{}}, nil, database.ErrUnsupportedDriver},
+		{"UnsupportedPool", open(connPool{}), []Import{{}}, nil, database.ErrUnsupportedDriver},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			if _, err := postgres.CopyFrom(result.DB, result.Values, result.Opts...); !errors.Is(err, result.Err) {
+				t.Errorf("error expects %v, got %v", result.Err, err)
+			}
+		})
+	}
+}
+
+func TestCopyFrom(t *testing.T) {
+	db := openDB(t, &Import{})
+
+	rows, err := postgres.CopyFrom(db, []Import{{Name: "first", Tags: []string{"a", "b"}}, {Name: "second"}})
+	if err != nil || rows != 2 {
+		t.Fatalf("no error should happen when copy slice, got %v rows, error %v", rows, err)
+	}
+
+	values := make(chan *Import)
+	go func() {
+		defer close(values)
+		for _, name := range []string{"third", "fourth", "fifth"} {
+			values <- &Import{Name: name}
+		}
+	}()
+
+	if rows, err := postgres.CopyFrom(db, values); err != nil || rows != 3 {
+		t.Fatalf("no error should happen when copy channel, got %v rows, error %v", rows, err)
+	}
+
+	var first Import
+	if err := db.First(&first, "name = ?", "first").Error; err != nil {
+		t.Fatalf("copied values should be found, got %v", err)
+	}
+
+	if len(first.Tags) != 2 || first.Tags[1] != "b" || first.CreatedAt.IsZero() {
+		t.Errorf("copied values should be serialized and timestamps set, got %+v", first)
+	}
+
+	rows, err = postgres.CopyFrom(db, []Import{{ID: first.ID, Name: "updated"}, {ID: 100, Name: "sixth"}}, postgres.CopyOptions{
+		Columns:    []string{"ID", "Name"},
+		OnConflict: &clause.OnConflict{UpdateAll: true},
+	})
+	if err != nil || rows != 2 {
+		t.Fatalf("no error should happen when merge copied values, got %v rows, error %v", rows, err)
+	}
+
+	var (
+		count   int64
+		updated Import
+	)
+	if db.Model(&Import{}).Count(&count); count != 6 {
+		t.Errorf("merged values should be inserted or updated, got %v rows", count)
+	}
+
+	if db.First(&updated, first.ID); updated.Name != "updated" || len(updated.Tags) != 2 {
+		t.Errorf("copied columns should be updated on conflict, got %+v", updated)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	sqlDB, err := sql.Open(sqlite.DriverName, filepath.Join(t.TempDir(), "imports.db"))
+	if err != nil {
+		t.Fatalf("failed to open sqlite, got error %v", err)
+	}
+	defer sqlDB.Close()
+
+	if _, err := sqlDB.Exec("CREATE TABLE imports (id integer PRIMARY KEY, name text)"); err != nil {
+		t.Fatalf("failed to create table, got error %v", err)
+	}
+
+	db, err := database.Open(postgres.New(postgres.Config{Conn: sqlDB}), &database.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	errRollback := errors.New("rollback")
+	err = postgres.Transaction(db, func(tx *database.DB) error {
+		tx.Exec("INSERT INTO imports (name) VALUES (?)", "rolled back")
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Errorf("error of fc should be returned, got %v", err)
+	}
+
+	err = postgres.Transaction(db, func(tx *database.DB) error {
+		if _, ok := tx.Statement.ConnPool.(database.TxCommitter); !ok {
+			t.Errorf("fc should run in a transaction, got %T", tx.Statement.ConnPool)
+		}
+		return tx.Exec("INSERT INTO imports (name) VALUES (?)", "committed").Error
+	})
+	if err != nil {
+		t.Fatalf("no error should happen when commit, got %v", err)
+	}
+
+	var names []string
+	if err := db.Table("imports").Pluck("name", &names).Error; err != nil || len(names) != 1 || names[0] != "committed" {
+		t.Errorf("only committed values should be found, got %v, error %v", names, err)
+	}
+
+	if db, err = database.Open(postgres.New(postgres.Config{Conn: connPool{}}), &database.Config{DisableAutomaticPing: true, Logger: logger.Discard}); err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	if err := postgres.Transaction(db, func(tx *database.DB) error { return nil }); !errors.Is(err, database.ErrUnsupportedDriver) {
+		t.Errorf("transactions of unsupported pools should fail, got %v", err)
+	}
+}
+
+func TestCopyFromInTransaction(t *testing.T) {
+	db := openDB(t, &Import{})
+
+	errRollback := errors.New("rollback")
+	copyImports := func(tx *database.DB) error {
+		if _, err := postgres.CopyFrom(tx, []Import{{Name: "copied"}}); err != nil {
+			return err
+		}
+
+		_, err := postgres.CopyFrom(tx, []Import{{ID: 100, Name: "merged"}}, postgres.CopyOptions{
+			OnConflict: &clause.OnConflict{UpdateAll: true},
+		})
+		return err
+	}
+
+	if err := postgres.Transaction(db, func(tx *database.DB) error {
+		if err := copyImports(tx); err != nil {
+			return err
+		}
+		return errRollback
+	}); !errors.Is(err, errRollback) {
+		t.Fatalf("error of fc should be returned, got %v", err)
+	}
+
+	var count int64
+	if db.Model(&Import{}).Count(&count); count != 0 {
+		t.Errorf("copied values should be rolled back with the transaction, got %v rows", count)
+	}
+
+	if err := postgres.Transaction(db, copyImports); err != nil {
+		t.Fatalf("no error should happen when copy in transaction, got %v", err)
+	}
+
+	if db.Model(&Import{}).Count(&count); count != 2 {
+		t.Errorf("copied values should be committed with the transaction, got %v rows", count)
+	}
+}
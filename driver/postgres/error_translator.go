@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/driver005/database"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+var errCodes = map[string]error{
+	"23505": database.ErrDuplicatedKey,
+	"23503": database.ErrForeignKeyViolated,
+	"23514": database.ErrCheckConstraintViolated,
+	"23502": database.ErrNotNullViolated,
+	"40P01": database.ErrDeadlock,
+	"40001": database.ErrSerializationFailure,
+	"55P03": database.ErrLockTimeout,
+}
+
+// Translate translate pgconn.PgError to portable errors
+func (dialector Dialector) Translate(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	if translatedErr, ok := errCodes[pgErr.Code]; ok {
+		return &database.TranslatedError{Err: translatedErr, Cause: err, Constraint: pgErr.ConstraintName, Table: pgErr.TableName}
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package postgres_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTranslate(t *testing.T) {
+	results := []struct {
+		Code string
+		Err  error
+	}{
+		{"23505", database.ErrDuplicatedKey},
+		{"23503", database.ErrForeignKeyViolated},
+		{"23514", database.ErrCheckConstraintViolated},
+		{"23502", database.ErrNotNullViolated},
+		{"40P01", database.ErrDeadlock},
+		{"40001", database.ErrSerializationFailure},
+		{"55P03", database.ErrLockTimeout},
+	}
+
+	for _, result := range results {
+		t.Run(result.Code, func(t *testing.T) {
+			pgErr := &pgconn.PgError{Code: result.Code, ConstraintName: "idx_users_name", TableName: "users"}
+			err := postgres.Dialector{}.Translate(fmt.Errorf("wrapped: %w", pgErr))
+			if !errors.Is(err, result.Err) {
+				t.Fatalf("expects %v, got %v", result.Err, err)
+			}
+
+			var translated *database.TranslatedError
+			if !errors.As(err, &translated) || translated.Constraint != "idx_users_name" || translated.Table != "users" {
+				t.Errorf("constraint and table should be translated, got %+v", translated)
+			}
+
+			var cause *pgconn.PgError
+			if !errors.As(err, &cause) || cause != pgErr {
+				t.Errorf("driver error should be reachable, got %v", cause)
+			}
+		})
+	}
+
+	for _, err := range []error{errors.New("unknown"), &pgconn.PgError{Code: "42P01"}} {
+		if translated := (postgres.Dialector{}).Translate(err); translated != err {
+			t.Errorf("error %v shouldn't be translated, got %v", err, translated)
+		}
+	}
+}
+
+func TestAddError(t *testing.T) {
+	db, _ := dryRunDB(t)
+
+	tx := db.Session(&database.Session{})
+	tx.AddError(&pgconn.PgError{Code: "23505"})
+	if !errors.Is(tx.Error, database.ErrDuplicatedKey) {
+		t.Fatalf("errors should be translated, got %v", tx.Error)
+	}
+
+	// translated errors aren't translated twice
+	translated := tx.Error
+	if tx = db.Session(&database.Session{}); tx.AddError(translated) != translated {
+		t.Errorf("translated errors shouldn't be translated again, got %v", tx.Error)
+	}
+}
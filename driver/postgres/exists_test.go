@@ -0,0 +1,49 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/driver005/database/logger"
+)
+
+var errQueried = errors.New("queried")
+
+// queryRecorder records SQL of queries without executing them
+type queryRecorder struct {
+	database.ConnPool
+	sqls []string
+}
+
+func (r *queryRecorder) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	r.sqls = append(r.sqls, query)
+	return nil, errQueried
+}
+
+type Post struct {
+	ID        uint
+	Title     string
+	DeletedAt database.DeletedAt
+}
+
+func TestExists(t *testing.T) {
+	conn := &queryRecorder{}
+	db, err := database.Open(postgres.New(postgres.Config{Conn: conn}), &database.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	var exists bool
+	if err := db.Model(&Post{}).Where("title = ?", "hello").Order("id").Exists(&exists).Error; !errors.Is(err, errQueried) {
+		t.Fatalf("query error should be returned, got %v", err)
+	}
+
+	expected := `SELECT EXISTS(SELECT 1 FROM "posts" WHERE title = $1 AND "posts"."deleted_at" IS NULL)`
+	if len(conn.sqls) != 1 || conn.sqls[0] != expected {
+		t.Errorf("SQL expects %v got %v", expected, conn.sqls)
+	}
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"reflect"
+
+	"github.com/driver005/database/clause"
+)
+
+// arrayValue driver.Valuer of slices and arrays, otherwise they are expanded to lists of values when added to SQL
+type arrayValue struct {
+	value interface{}
+}
+
+func (v arrayValue) Value() (driver.Value, error) {
+	return encodeArray(v.value)
+}
+
+// hstoreValue driver.Valuer of maps
+type hstoreValue struct {
+	value interface{}
+}
+
+func (v hstoreValue) Value() (driver.Value, error) {
+	return encodeHstore(v.value)
+}
+
+// valueOf returns the value of operands, slices and arrays are encoded to postgres arrays, maps to hstore
+func valueOf(value interface{}) interface{} {
+	switch value.(type) {
+	case clause.Expression, driver.Valuer, []byte:
+		return value
+	}
+
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return arrayValue{value: value}
+	case reflect.Map:
+		return hstoreValue{value: value}
+	}
+	return value
+}
+
+func buildOperator(builder clause.Builder, column interface{}, op string, value interface{}) {
+	builder.WriteQuoted(column)
+	builder.WriteString(op)
+	builder.AddVar(builder, valueOf(value))
+}
+
+// Contains column @> value, the array, hstore or range column contains value
+//
+//	db.Where(postgres.Contains{Column: "tags", Value: []string{"go"}}).Find(&posts)
+type Contains struct {
+	Column interface{}
+	Value  interface{}
+}
+
+func (c Contains) Build(builder clause.Builder) {
+	buildOperator(builder, c.Column, " @> ", c.Value)
+}
+
+// ContainedBy column <@ value, the array, hstore or range column is contained by value
+type ContainedBy struct {
+	Column interface{}
+	Value  interface{}
+}
+
+func (c ContainedBy) Build(builder clause.Builder) {
+	buildOperator(builder, c.Column, " <@ ", c.Value)
+}
+
+// Overlaps column && value, the array or range column has elements in common with value
+type Overlaps struct {
+	Column interface{}
+	Value  interface{}
+}
+
+func (o Overlaps) Build(builder clause.Builder) {
+	buildOperator(builder, o.Column, " && ", o.Value)
+}
+
+// Any column = ANY(values), column equals any element of values, a slice or array column
+//
+//	db.Where(postgres.Any{Column: "id", Values: ids}).Find(&users)
+type Any struct {
+	Column interface{}
+	Values interface{}
+}
+
+func (a Any) Build(builder clause.Builder) {
+	builder.WriteQuoted(a.Column)
+	builder.WriteString(" = ANY(")
+	builder.AddVar(builder, valueOf(a.Values))
+	builder.WriteByte(')')
+}
+
+func (a Any) NegationBuild(builder clause.Builder) {
+	builder.WriteQuoted(a.Column)
+	builder.WriteString(" <> ALL(")
+	builder.AddVar(builder, valueOf(a.Values))
+	builder.WriteByte(')')
+}
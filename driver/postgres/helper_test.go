@@ -0,0 +1,39 @@
+package postgres_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/driver005/database/logger"
+)
+
+// openDB opens the postgres database of POSTGRES_DSN and migrates models, tests are skipped if it isn't set
+func openDB(t *testing.T, models ...interface{}) *database.DB {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN isn't set")
+	}
+
+	db, err := database.Open(postgres.Open(dsn), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Migrator().DropTable(models...)
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := db.Migrator().DropTable(models...); err != nil {
+		t.Fatalf("failed to drop tables, got error %v", err)
+	}
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	return db
+}
@@ -729,3 +729,63 @@ func (m Migrator) RenameColumn(dst interface{}, oldName, field string) error {
 	m.resetPreparedStmts()
 	return nil
 }
+
+// HasView check view or materialized view exists in the current schema, or the schema of name like "schema.view"
+func (m Migrator) HasView(name string) bool {
+	var count int64
+	currentSchema, view := m.CurrentSchema(m.DB.Statement, name)
+	m.DB.Raw(
+		"SELECT count(*) FROM (SELECT schemaname, viewname FROM pg_views UNION ALL SELECT schemaname, matviewname FROM pg_matviews) AS v WHERE v.schemaname = ? AND v.viewname = ?",
+		currentSchema, view,
+	).Scan(&count)
+
+	return count > 0
+}
+
+// MaterializedViewOption materialized view option
+type MaterializedViewOption struct {
+	// Replace drops the existing materialized view first as postgres has no OR REPLACE for it
+	Replace bool
+	// WithNoData creates the materialized view unpopulated, it can't be queried until it's refreshed
+	WithNoData bool
+	Query      *database.DB
+}
+
+// CreateMaterializedView create materialized view from option's Query
+//
+//	// CREATE MATERIALIZED VIEW "user_stats" AS SELECT ... WITH NO DATA
+//	db.Migrator().(postgres.Migrator).CreateMaterializedView("user_stats", postgres.MaterializedViewOption{Query: query, WithNoData: true})
+func (m Migrator) CreateMaterializedView(name string, option MaterializedViewOption) error {
+	query, err := m.BuildViewQuery(database.ViewOption{Query: option.Query})
+	if err != nil {
+		return err
+	}
+
+	if option.Replace {
+		if err := m.DropMaterializedView(name); err != nil {
+			return err
+		}
+	}
+
+	createViewSQL := "CREATE MATERIALIZED VIEW ? AS ?"
+	if option.WithNoData {
+		createViewSQL += " WITH NO DATA"
+	}
+
+	return m.DB.Exec(createViewSQL, clause.Table{Name: name}, clause.Expr{SQL: query}).Error
+}
+
+// DropMaterializedView drop materialized view if it exists
+func (m Migrator) DropMaterializedView(name string) error {
+	return m.DB.Exec("DROP MATERIALIZED VIEW IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// RefreshMaterializedView refresh materialized view's data, concurrently refresh requires an unique index on the view
+func (m Migrator) RefreshMaterializedView(name string, concurrently bool) error {
+	refreshSQL := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		refreshSQL += "CONCURRENTLY "
+	}
+
+	return m.DB.Exec(refreshSQL+"?", clause.Table{Name: name}).Error
+}
@@ -0,0 +1,135 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/driver005/database/logger"
+)
+
+type User struct {
+	ID   uint
+	Name string
+	Age  int
+}
+
+// recorder records SQL of executed statements, statements aren't executed in DryRun mode
+type recorder struct {
+	logger.Interface
+	sqls []string
+}
+
+func (r *recorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *recorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.sqls = append(r.sqls, sql)
+}
+
+func dryRunDB(t *testing.T) (*database.DB, *recorder) {
+	r := &recorder{Interface: logger.Discard}
+	db, err := database.Open(postgres.Open("host=localhost"), &database.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 r,
+	})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+	return db, r
+}
+
+func TestViews(t *testing.T) {
+	results := []struct {
+		Name    string
+		Migrate func(db *database.DB) error
+		SQLs    []string
+	}{
+		{
+			"CreateView",
+			func(db *database.DB) error {
+				return db.Migrator().CreateView("adult_users", database.ViewOption{Query: db.Model(&User{}).Where("age > ?", 20)})
+			},
+			[]string{`CREATE VIEW "adult_users" AS SELECT * FROM "users" WHERE age > 20`},
+		},
+		{
+			"CreateOrReplaceViewWithCheckOption",
+			func(db *database.DB) error {
+				return db.Migrator().CreateView("adult_users", database.ViewOption{
+					Query: db.Model(&User{}).Where("age > ?", 20), Replace: true, CheckOption: "WITH LOCAL CHECK OPTION",
+				})
+			},
+			[]string{`CREATE OR REPLACE VIEW "adult_users" AS SELECT * FROM "users" WHERE age > 20 WITH LOCAL CHECK OPTION`},
+		},
+		{
+			"DropView",
+			func(db *database.DB) error { return db.Migrator().DropView("adult_users") },
+			[]string{`DROP VIEW IF EXISTS "adult_users"`},
+		},
+		{
+			"CreateMaterializedView",
+			func(db *database.DB) error {
+				return db.Migrator().(postgres.Migrator).CreateMaterializedView("user_names", postgres.MaterializedViewOption{
+					Query: db.Model(&User{}).Select("name"),
+				})
+			},
+			[]string{`CREATE MATERIALIZED VIEW "user_names" AS SELECT "name" FROM "users"`},
+		},
+		{
+			"ReplaceMaterializedViewWithNoData",
+			func(db *database.DB) error {
+				return db.Migrator().(postgres.Migrator).CreateMaterializedView("user_names", postgres.MaterializedViewOption{
+					Query: db.Model(&User{}).Select("name"), Replace: true, WithNoData: true,
+				})
+			},
+			[]string{
+				`DROP MATERIALIZED VIEW IF EXISTS "user_names"`,
+				`CREATE MATERIALIZED VIEW "user_names" AS SELECT "name" FROM "users" WITH NO DATA`,
+			},
+		},
+		{
+			"RefreshMaterializedViewConcurrently",
+			func(db *database.DB) error {
+				return db.Migrator().(postgres.Migrator).RefreshMaterializedView("user_names", true)
+			},
+			[]string{`REFRESH MATERIALIZED VIEW CONCURRENTLY "user_names"`},
+		},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			db, r := dryRunDB(t)
+			if err := result.Migrate(db); err != nil {
+				t.Fatalf("no error should happen, got %v", err)
+			}
+
+			if len(r.sqls) != len(result.SQLs) {
+				t.Fatalf("SQLs expects %v got %v", result.SQLs, r.sqls)
+			}
+			for idx, sql := range result.SQLs {
+				if r.sqls[idx] != sql {
+					t.Errorf("SQL expects %v got %v", sql, r.sqls[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestCreateViewWithoutQuery(t *testing.T) {
+	db, _ := dryRunDB(t)
+	if err := db.Migrator().CreateView("adult_users", database.ViewOption{}); !errors.Is(err, database.ErrSubQueryRequired) {
+		t.Errorf("expects ErrSubQueryRequired, got %v", err)
+	}
+
+	err := db.Migrator().(postgres.Migrator).CreateMaterializedView("user_names", postgres.MaterializedViewOption{})
+	if !errors.Is(err, database.ErrSubQueryRequired) {
+		t.Errorf("expects ErrSubQueryRequired, got %v", err)
+	}
+}
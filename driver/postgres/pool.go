@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Pool connection pool over a pgxpool.Pool, database/sql connections are acquired from the pgx pool with pgx's stdlib
+// driver, so connection settings, statement caches of the connections and pool metrics are shared with code using the
+// pgx pool directly. The pgx pool isn't closed by Close, it's owned by the caller
+//
+// Pool isn't a native pgx mode, statements run through database/sql: ConnPool, Rows and transactions are defined on
+// database/sql types, e.g: Rows.ColumnTypes returns *sql.ColumnType, which can't be created outside of database/sql.
+// Run statements on PgxPool to use pgx directly
+//
+//	pool, err := pgxpool.New(ctx, dsn)
+//	db, err := database.Open(postgres.New(postgres.Config{Conn: postgres.NewPool(pool)}), &database.Config{})
+type Pool struct {
+	*sql.DB
+	pgxPool *pgxpool.Pool
+}
+
+// NewPool returns the connection pool of pgxPool
+func NewPool(pgxPool *pgxpool.Pool) *Pool {
+	return &Pool{DB: stdlib.OpenDBFromPool(pgxPool), pgxPool: pgxPool}
+}
+
+// PgxPool returns the underlying pgxpool.Pool
+func (p *Pool) PgxPool() *pgxpool.Pool {
+	return p.pgxPool
+}
+
+// Stat returns statistics of the pgx pool
+func (p *Pool) Stat() *pgxpool.Stat {
+	return p.pgxPool.Stat()
+}
+
+// GetDBConn returns the *sql.DB of the pool
+func (p *Pool) GetDBConn() (*sql.DB, error) {
+	return p.DB, nil
+}
@@ -0,0 +1,93 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/driver005/database/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestNewPool(t *testing.T) {
+	config, err := pgxpool.ParseConfig("host=localhost port=1 user=database pool_max_conns=3")
+	if err != nil {
+		t.Fatalf("failed to parse config, got error %v", err)
+	}
+
+	// connections are established lazily, so the pool is created without a database
+	pgxPool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("failed to create pgx pool, got error %v", err)
+	}
+	defer pgxPool.Close()
+
+	pool := postgres.NewPool(pgxPool)
+	if pool.PgxPool() != pgxPool {
+		t.Errorf("pgx pool should be returned")
+	}
+
+	if stat := pool.Stat(); stat.MaxConns() != 3 || stat.TotalConns() != 0 {
+		t.Errorf("stat of the pgx pool should be returned, got max %v, total %v", stat.MaxConns(), stat.TotalConns())
+	}
+
+	db, err := database.Open(postgres.New(postgres.Config{Conn: pool}), &database.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	if sqlDB, err := db.DB(); err != nil || sqlDB != pool.DB {
+		t.Errorf("*sql.DB of the pool should be returned, got %v, error %v", sqlDB, err)
+	}
+
+	if _, ok := db.ConnPool.(database.TxBeginner); !ok {
+		t.Errorf("pool should begin transactions")
+	}
+}
+
+func TestPool(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN isn't set")
+	}
+
+	pgxPool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("failed to create pgx pool, got error %v", err)
+	}
+	defer pgxPool.Close()
+
+	pool := postgres.NewPool(pgxPool)
+	db, err := database.Open(postgres.New(postgres.Config{Conn: pool}), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	db.Migrator().DropTable(&Import{})
+	defer db.Migrator().DropTable(&Import{})
+	if err := db.AutoMigrate(&Import{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if rows, err := postgres.CopyFrom(db, []Import{{Name: "copied"}}); err != nil || rows != 1 {
+		t.Fatalf("no error should happen when copy with pool, got %v rows, error %v", rows, err)
+	}
+
+	err = db.Transaction(func(tx *database.DB) error {
+		return tx.Create(&Import{Name: "created", Tags: []string{"a"}}).Error
+	})
+	if err != nil {
+		t.Fatalf("no error should happen when create in transaction, got %v", err)
+	}
+
+	var count int64
+	if db.Model(&Import{}).Count(&count); count != 2 || pool.Stat().TotalConns() == 0 {
+		t.Errorf("records should be created with connections of the pgx pool, got %v", count)
+	}
+
+	if err := pool.Close(); err != nil || pgxPool.Ping(context.Background()) != nil {
+		t.Errorf("pgx pool shouldn't be closed by Close, got %v", err)
+	}
+}
@@ -12,8 +12,8 @@ import (
 	"github.com/driver005/database/logger"
 	"github.com/driver005/database/migrator"
 	"github.com/driver005/database/schema"
-	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 type Dialector struct {
@@ -45,9 +45,9 @@ var timeZoneMatcher = regexp.MustCompile("(time_zone|TimeZone)=(.*?)($|&| )")
 func (dialector Dialector) Initialize(db *database.DB) (err error) {
 	// register callbacks
 	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{
-		CreateClauses: []string{"INSERT", "VALUES", "ON CONFLICT", "RETURNING"},
-		UpdateClauses: []string{"UPDATE", "SET", "WHERE", "RETURNING"},
-		DeleteClauses: []string{"DELETE", "FROM", "WHERE", "RETURNING"},
+		CreateClauses: []string{"WITH", "INSERT", "VALUES", "ON CONFLICT", "RETURNING"},
+		UpdateClauses: []string{"WITH", "UPDATE", "SET", "WHERE", "RETURNING"},
+		DeleteClauses: []string{"WITH", "DELETE", "FROM", "WHERE", "RETURNING"},
 	})
 
 	if dialector.Conn != nil {
@@ -62,7 +62,7 @@ func (dialector Dialector) Initialize(db *database.DB) (err error) {
 			return
 		}
 		if dialector.Config.PreferSimpleProtocol {
-			config.PreferSimpleProtocol = true
+			config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
 		}
 		result := timeZoneMatcher.FindStringSubmatch(dialector.Config.DSN)
 		if len(result) > 2 {
@@ -192,11 +192,30 @@ func (dialector Dialector) DataTypeOf(field *schema.Field) string {
 		return "timestamptz"
 	case schema.Bytes:
 		return "bytea"
+	case schema.Array:
+		if _, sqlType := arrayElemType(field.IndirectFieldType.Elem()); sqlType != "" {
+			return sqlType + "[]"
+		}
+	case schema.Map:
+		return "hstore"
+	case schema.JSON:
+		return "jsonb"
 	}
 
 	return string(field.DataType)
 }
 
+// DataTypeSerializer serializes arrays to postgres arrays and maps to hstore
+func (dialector Dialector) DataTypeSerializer(dataType schema.DataType) (schema.SerializerInterface, bool) {
+	switch dataType {
+	case schema.Array:
+		return ArraySerializer{}, true
+	case schema.Map:
+		return HstoreSerializer{}, true
+	}
+	return nil, false
+}
+
 func (dialectopr Dialector) SavePoint(tx *database.DB, name string) error {
 	tx.Exec("SAVEPOINT " + name)
 	return nil
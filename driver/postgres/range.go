@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// bound types of ranges
+const (
+	Inclusive = pgtype.Inclusive
+	Exclusive = pgtype.Exclusive
+	Unbounded = pgtype.Unbounded
+	Empty     = pgtype.Empty
+)
+
+// NewRange returns the range [lower, upper), set LowerType or UpperType to Unbounded for ranges without bounds
+//
+//	db.Create(&Reservation{During: postgres.TstzRange(postgres.NewRange(start, end))})
+func NewRange[T any](lower, upper T) pgtype.Range[T] {
+	return pgtype.Range[T]{Lower: lower, Upper: upper, LowerType: Inclusive, UpperType: Exclusive, Valid: true}
+}
+
+// scanRange scans src, a range of the text format, into r
+func scanRange[T any](name string, src interface{}, r *pgtype.Range[T]) error {
+	if src == nil {
+		*r = pgtype.Range[T]{}
+		return nil
+	}
+
+	buf, err := bytesOf(src)
+	if err != nil {
+		return err
+	}
+
+	m := typeMaps.Get().(*pgtype.Map)
+	defer typeMaps.Put(m)
+
+	t, ok := m.TypeForName(name)
+	if !ok {
+		return fmt.Errorf("unsupported range type %s", name)
+	}
+	return m.Scan(t.OID, pgtype.TextFormatCode, buf, r)
+}
+
+// rangeValue encodes r to the text format of ranges, e.g: [1,10)
+func rangeValue[T any](name string, r pgtype.Range[T]) (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	m := typeMaps.Get().(*pgtype.Map)
+	defer typeMaps.Put(m)
+
+	t, ok := m.TypeForName(name)
+	if !ok {
+		return nil, fmt.Errorf("unsupported range type %s", name)
+	}
+
+	buf, err := m.Encode(t.OID, pgtype.TextFormatCode, r, nil)
+	if err != nil || buf == nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// Int4Range int4range of int32 bounds
+type Int4Range pgtype.Range[int32]
+
+func (Int4Range) DBDataType() string {
+	return "int4range"
+}
+
+// Scan implements sql.Scanner interface
+func (r *Int4Range) Scan(src interface{}) error {
+	return scanRange("int4range", src, (*pgtype.Range[int32])(r))
+}
+
+// Value implements driver.Valuer interface
+func (r Int4Range) Value() (driver.Value, error) {
+	return rangeValue("int4range", pgtype.Range[int32](r))
+}
+
+// Int8Range int8range of int64 bounds
+type Int8Range pgtype.Range[int64]
+
+func (Int8Range) DBDataType() string {
+	return "int8range"
+}
+
+// Scan implements sql.Scanner interface
+func (r *Int8Range) Scan(src interface{}) error {
+	return scanRange("int8range", src, (*pgtype.Range[int64])(r))
+}
+
+// Value implements driver.Valuer interface
+func (r Int8Range) Value() (driver.Value, error) {
+	return rangeValue("int8range", pgtype.Range[int64](r))
+}
+
+// TsRange tsrange of time bounds without time zone
+type TsRange pgtype.Range[time.Time]
+
+func (TsRange) DBDataType() string {
+	return "tsrange"
+}
+
+// Scan implements sql.Scanner interface
+func (r *TsRange) Scan(src interface{}) error {
+	return scanRange("tsrange", src, (*pgtype.Range[time.Time])(r))
+}
+
+// Value implements driver.Valuer interface
+func (r TsRange) Value() (driver.Value, error) {
+	return rangeValue("tsrange", pgtype.Range[time.Time](r))
+}
+
+// TstzRange tstzrange of time bounds with time zone
+type TstzRange pgtype.Range[time.Time]
+
+func (TstzRange) DBDataType() string {
+	return "tstzrange"
+}
+
+// Scan implements sql.Scanner interface
+func (r *TstzRange) Scan(src interface{}) error {
+	return scanRange("tstzrange", src, (*pgtype.Range[time.Time])(r))
+}
+
+// Value implements driver.Valuer interface
+func (r TstzRange) Value() (driver.Value, error) {
+	return rangeValue("tstzrange", pgtype.Range[time.Time](r))
+}
+
+// DateRange daterange of date bounds, time of day of bounds is ignored
+type DateRange pgtype.Range[time.Time]
+
+func (DateRange) DBDataType() string {
+	return "daterange"
+}
+
+// Scan implements sql.Scanner interface
+func (r *DateRange) Scan(src interface{}) error {
+	return scanRange("daterange", src, (*pgtype.Range[time.Time])(r))
+}
+
+// Value implements driver.Valuer interface
+func (r DateRange) Value() (driver.Value, error) {
+	return rangeValue("daterange", pgtype.Range[time.Time](r))
+}
@@ -0,0 +1,59 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/driver005/database"
+)
+
+type Topic struct {
+	ID        uint
+	Name      string
+	IsDeleted database.SoftDeleteFlag `database:"not null;default:false"`
+}
+
+func TestSoftDeleteFlag(t *testing.T) {
+	db, r := dryRunDB(t)
+	db.Migrator().CreateTable(&Topic{})
+	db.Delete(&Topic{ID: 1})
+	db.Find(&[]Topic{})
+	db.Restore(&Topic{}, 1)
+
+	expected := []string{
+		`CREATE TABLE "topics" ("id" bigserial,"name" text,"is_deleted" boolean NOT NULL DEFAULT false,PRIMARY KEY ("id"))`,
+		`UPDATE "topics" SET "is_deleted"=true WHERE "topics"."id" = 1 AND "topics"."is_deleted" = false`,
+		`SELECT * FROM "topics" WHERE "topics"."is_deleted" = false`,
+		`UPDATE "topics" SET "is_deleted"=false WHERE "topics"."id" = 1 AND "topics"."is_deleted" <> false`,
+	}
+	if len(r.sqls) != len(expected) {
+		t.Fatalf("SQLs expects %v got %v", expected, r.sqls)
+	}
+	for idx, sql := range expected {
+		if r.sqls[idx] != sql {
+			t.Errorf("SQL expects %v got %v", sql, r.sqls[idx])
+		}
+	}
+}
+
+func TestSoftDeleteFlagColumn(t *testing.T) {
+	db := openDB(t, &Topic{})
+
+	topics := []Topic{{Name: "deleted"}, {Name: "kept"}}
+	if err := db.Create(&topics).Error; err != nil {
+		t.Fatalf("no error should happen when create topics, got %v", err)
+	}
+
+	if err := db.Delete(&topics[0]).Error; err != nil {
+		t.Fatalf("no error should happen when delete topic, got %v", err)
+	}
+
+	var names []string
+	if err := db.Model(&Topic{}).Order("id").Pluck("name", &names).Error; err != nil || len(names) != 1 || names[0] != "kept" {
+		t.Errorf("deleted topics shouldn't be found, got %v, error %v", names, err)
+	}
+
+	var deleted Topic
+	if err := db.Unscoped().First(&deleted, topics[0].ID).Error; err != nil || !deleted.IsDeleted {
+		t.Errorf("deleted flag should be true, got %v, error %v", deleted.IsDeleted, err)
+	}
+}
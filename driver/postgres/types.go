@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/driver005/database/schema"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// typeMaps pool of pgtype.Map, a pgtype.Map caches encode and scan plans and isn't safe for concurrent use
+var typeMaps = sync.Pool{
+	New: func() interface{} {
+		return pgtype.NewMap()
+	},
+}
+
+var uuidType = reflect.TypeOf([16]byte{})
+
+// isNamedUUIDs returns true if rt is a slice of named uuids, e.g: []uuid.UUID, pgx only encodes and scans [16]byte
+func isNamedUUIDs(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Array && rt.Elem() != uuidType &&
+		rt.Elem().ConvertibleTo(uuidType)
+}
+
+// convertSlice converts elements of slice rv to elemType
+func convertSlice(rv reflect.Value, elemType reflect.Type) reflect.Value {
+	if rv.IsNil() {
+		return reflect.Zero(reflect.SliceOf(elemType))
+	}
+
+	result := reflect.MakeSlice(reflect.SliceOf(elemType), rv.Len(), rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		result.Index(i).Set(rv.Index(i).Convert(elemType))
+	}
+	return result
+}
+
+// arrayElemType returns the pgx type name and the SQL type of array elements of type rt
+func arrayElemType(rt reflect.Type) (name string, sqlType string) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool:
+		return "bool", "boolean"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "int2", "smallint"
+	case reflect.Int32, reflect.Uint16:
+		return "int4", "integer"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "int8", "bigint"
+	case reflect.Float32:
+		return "float4", "real"
+	case reflect.Float64:
+		return "float8", "double precision"
+	case reflect.String:
+		return "text", "text"
+	case reflect.Array:
+		return "uuid", "uuid"
+	case reflect.Struct:
+		return "timestamptz", "timestamp with time zone"
+	}
+	return "", ""
+}
+
+// arrayOID returns the oid of the array type of slices or arrays of type rt
+func arrayOID(m *pgtype.Map, rt reflect.Type) (uint32, error) {
+	name, _ := arrayElemType(rt.Elem())
+	if t, ok := m.TypeForName("_" + name); ok {
+		return t.OID, nil
+	}
+	return 0, fmt.Errorf("unsupported array element type %v", rt.Elem())
+}
+
+// encodeArray encodes value, a slice or array, to the text format of postgres arrays, e.g: {1,2,3}
+func encodeArray(value interface{}) (driver.Value, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return nil, nil
+	}
+
+	m := typeMaps.Get().(*pgtype.Map)
+	defer typeMaps.Put(m)
+
+	oid, err := arrayOID(m, rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	if isNamedUUIDs(rv.Type()) {
+		value = convertSlice(rv, uuidType).Interface()
+	}
+
+	buf, err := m.Encode(oid, pgtype.TextFormatCode, value, nil)
+	if err != nil || buf == nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// encodeHstore encodes value, a map of string keys and string or *string values, to the text format of hstore
+func encodeHstore(value interface{}) (driver.Value, error) {
+	rv := reflect.ValueOf(value)
+	if rv.IsNil() {
+		return nil, nil
+	}
+
+	hstore := make(pgtype.Hstore, rv.Len())
+	for iter := rv.MapRange(); iter.Next(); {
+		v := iter.Value()
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				hstore[iter.Key().String()] = nil
+				continue
+			}
+			v = v.Elem()
+		}
+
+		str := v.String()
+		hstore[iter.Key().String()] = &str
+	}
+	return hstore.Value()
+}
+
+// newFieldValue returns a new value of field's type, target is the value to scan into, pointer fields are allocated
+func newFieldValue(field *schema.Field) (fieldValue reflect.Value, target reflect.Value) {
+	fieldValue = reflect.New(field.FieldType)
+	target = fieldValue
+	if field.FieldType.Kind() == reflect.Ptr {
+		fieldValue.Elem().Set(reflect.New(field.IndirectFieldType))
+		target = fieldValue.Elem()
+	}
+	return
+}
+
+func bytesOf(dbValue interface{}) ([]byte, error) {
+	switch v := dbValue.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("failed to scan value: %#v", dbValue)
+}
+
+// ArraySerializer serializer of array fields, e.g: []string, []int64, []uuid.UUID, values are encoded to and decoded
+// from postgres arrays with pgx, elements of NULL are scanned into pointer elements only, e.g: []*string
+type ArraySerializer struct {
+}
+
+// Scan implements serializer interface
+func (ArraySerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) (err error) {
+	if dbValue == nil {
+		field.ReflectValueOf(ctx, dst).Set(reflect.Zero(field.FieldType))
+		return nil
+	}
+
+	src, err := bytesOf(dbValue)
+	if err != nil {
+		return err
+	}
+
+	m := typeMaps.Get().(*pgtype.Map)
+	defer typeMaps.Put(m)
+
+	oid, err := arrayOID(m, field.IndirectFieldType)
+	if err != nil {
+		return err
+	}
+
+	fieldValue, target := newFieldValue(field)
+	if isNamedUUIDs(field.IndirectFieldType) {
+		var uuids [][16]byte
+		if err = m.Scan(oid, pgtype.TextFormatCode, src, &uuids); err != nil {
+			return err
+		}
+		target.Elem().Set(convertSlice(reflect.ValueOf(uuids), field.IndirectFieldType.Elem()).Convert(field.IndirectFieldType))
+	} else if err = m.Scan(oid, pgtype.TextFormatCode, src, target.Interface()); err != nil {
+		return err
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements serializer interface
+func (ArraySerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if rv := reflect.ValueOf(fieldValue); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		fieldValue = rv.Elem().Interface()
+	}
+	return encodeArray(fieldValue)
+}
+
+// HstoreSerializer serializer of hstore fields, e.g: map[string]string, map[string]*string, values of NULL are
+// scanned into maps of pointer values only, the hstore extension has to be created before migrating
+type HstoreSerializer struct {
+}
+
+// Scan implements serializer interface
+func (HstoreSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) (err error) {
+	if dbValue == nil {
+		field.ReflectValueOf(ctx, dst).Set(reflect.Zero(field.FieldType))
+		return nil
+	}
+
+	var hstore pgtype.Hstore
+	if err = hstore.Scan(dbValue); err != nil {
+		return err
+	}
+
+	fieldValue, target := newFieldValue(field)
+	var (
+		mapType  = field.IndirectFieldType
+		mapValue = reflect.MakeMapWithSize(mapType, len(hstore))
+	)
+
+	for k, v := range hstore {
+		key := reflect.New(mapType.Key()).Elem()
+		key.SetString(k)
+
+		value := reflect.New(mapType.Elem()).Elem()
+		if mapType.Elem().Kind() == reflect.Ptr {
+			if v == nil {
+				mapValue.SetMapIndex(key, value)
+				continue
+			}
+			value.Set(reflect.New(mapType.Elem().Elem()))
+			value.Elem().SetString(*v)
+		} else if v != nil {
+			value.SetString(*v)
+		} else {
+			continue
+		}
+		mapValue.SetMapIndex(key, value)
+	}
+
+	target.Elem().Set(mapValue)
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements serializer interface
+func (HstoreSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if rv := reflect.ValueOf(fieldValue); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		fieldValue = rv.Elem().Interface()
+	}
+	return encodeHstore(fieldValue)
+}
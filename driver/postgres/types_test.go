@@ -0,0 +1,193 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/driver/postgres"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/schema"
+)
+
+type UUID [16]byte
+
+type Document struct {
+	ID     uint
+	Tags   []string
+	Scores []int64
+	Refs   []UUID
+	Times  []time.Time
+	Notes  []*string
+	Attrs  map[string]string
+	Labels map[string]*string
+	Pages  postgres.Int4Range
+	During postgres.TstzRange
+}
+
+func TestDataTypeSerializer(t *testing.T) {
+	db, _ := dryRunDB(t)
+	stmt := &database.Statement{DB: db}
+	if err := stmt.Parse(&Document{}); err != nil {
+		t.Fatalf("failed to parse, got error %v", err)
+	}
+
+	for name, dataType := range map[string]string{"Tags": "text[]", "Scores": "bigint[]", "Refs": "uuid[]", "Attrs": "hstore", "Pages": "int4range"} {
+		field := stmt.Schema.LookUpField(name)
+		if got := db.Dialector.DataTypeOf(field); got != dataType {
+			t.Errorf("%v: data type expects %v, got %v", name, dataType, got)
+		}
+
+		if name != "Pages" && field.Serializer == nil {
+			t.Errorf("%v: arrays and maps should be serialized by postgres", name)
+		}
+	}
+
+	sqliteDB, err := database.Open(sqlite.Open(":memory:"), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open sqlite, got error %v", err)
+	}
+
+	stmt = &database.Statement{DB: sqliteDB}
+	if err := stmt.Parse(&Document{}); !errors.Is(err, schema.ErrUnsupportedDataType) {
+		t.Errorf("arrays and maps should be unsupported in other databases, got %v", err)
+	}
+}
+
+func TestTypesRoundTrip(t *testing.T) {
+	db, recorder := dryRunDB(t)
+	note := "note"
+	document := Document{
+		Tags:   []string{"go", "b c", `q"x`},
+		Scores: []int64{1, 2},
+		Refs:   []UUID{{1, 2}},
+		Times:  []time.Time{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Notes:  []*string{&note, nil},
+		Attrs:  map[string]string{"k": "v"},
+		Labels: map[string]*string{"n": nil},
+		Pages:  postgres.Int4Range(postgres.NewRange[int32](1, 10)),
+		During: postgres.TstzRange(postgres.NewRange(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))),
+	}
+
+	db.Create(&document)
+	expected := `INSERT INTO "documents" ("tags","scores","refs","times","notes","attrs","labels","pages","during") VALUES ` +
+		`('{go,b c,"q\"x"}','{1,2}','{01020000-0000-0000-0000-000000000000}','{2024-01-02 03:04:05Z}','{note,NULL}',` +
+		`'"k"=>"v"','"n"=>NULL','[1,10)','[2024-01-02 00:00:00Z,2024-01-05 00:00:00Z)') RETURNING "id"`
+	if len(recorder.sqls) != 1 || recorder.sqls[0] != expected {
+		t.Errorf("SQL expects %v got %v", expected, recorder.sqls)
+	}
+
+	stmt := &database.Statement{DB: db}
+	if err := stmt.Parse(&Document{}); err != nil {
+		t.Fatalf("failed to parse, got error %v", err)
+	}
+
+	var (
+		ctx     = context.Background()
+		scanned Document
+	)
+	for _, field := range stmt.Schema.Fields {
+		if field.Name == "ID" {
+			continue
+		}
+
+		value, _ := field.ValueOf(ctx, reflect.ValueOf(&document))
+		dbValue, err := value.(driver.Valuer).Value()
+		if err != nil {
+			t.Fatalf("%v: no error should happen when encode, got %v", field.Name, err)
+		}
+
+		// scanned like rows, into values of the field's pool
+		scanner := field.NewValuePool.Get()
+		if err := scan(scanner, dbValue); err != nil {
+			t.Fatalf("%v: no error should happen when scan %v, got %v", field.Name, dbValue, err)
+		}
+
+		if err := field.Set(ctx, reflect.ValueOf(&scanned), scanner); err != nil {
+			t.Fatalf("%v: no error should happen when set %v, got %v", field.Name, dbValue, err)
+		}
+		field.NewValuePool.Put(scanner)
+	}
+
+	if !reflect.DeepEqual(document, scanned) {
+		t.Errorf("values should be scanned back, expects %#v, got %#v", document, scanned)
+	}
+}
+
+// scan scans src into dest like database/sql, dest is a scanner or a pointer to a pointer of scanners
+func scan(dest interface{}, src interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	ptr := reflect.ValueOf(dest).Elem()
+	ptr.Set(reflect.New(ptr.Type().Elem()))
+	return ptr.Interface().(sql.Scanner).Scan(src)
+}
+
+func TestOperators(t *testing.T) {
+	db, recorder := dryRunDB(t)
+
+	results := []struct {
+		Name string
+		Expr clause.Expression
+		SQL  string
+	}{
+		{"Contains", postgres.Contains{Column: "tags", Value: []string{"go", "db"}}, `"tags" @> '{go,db}'`},
+		{"ContainsHstore", postgres.Contains{Column: "attrs", Value: map[string]string{"k": "v"}}, `"attrs" @> '"k"=>"v"'`},
+		{"ContainedBy", postgres.ContainedBy{Column: clause.Column{Table: clause.CurrentTable, Name: "scores"}, Value: []int64{1, 2}}, `"documents"."scores" <@ '{1,2}'`},
+		{"Overlaps", postgres.Overlaps{Column: "pages", Value: postgres.Int4Range(postgres.NewRange[int32](5, 20))}, `"pages" && '[5,20)'`},
+		{"Any", postgres.Any{Column: "id", Values: []uint{1, 2}}, `"id" = ANY('{1,2}')`},
+		{"NotAny", clause.Not(postgres.Any{Column: "id", Values: []uint{1, 2}}), `"id" <> ALL('{1,2}')`},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			recorder.sqls = nil
+			db.Where(result.Expr).Find(&[]Document{})
+
+			expected := `SELECT * FROM "documents" WHERE ` + result.SQL
+			if len(recorder.sqls) != 1 || recorder.sqls[0] != expected {
+				t.Errorf("SQL expects %v got %v", expected, recorder.sqls)
+			}
+		})
+	}
+}
+
+func TestTypes(t *testing.T) {
+	if db := openDB(t); db.Exec("CREATE EXTENSION IF NOT EXISTS hstore").Error != nil {
+		t.Skip("hstore extension isn't available")
+	}
+	db := openDB(t, &Document{})
+
+	note := "note"
+	document := Document{
+		Tags:   []string{"go", "db"},
+		Scores: []int64{1, 2},
+		Notes:  []*string{&note, nil},
+		Attrs:  map[string]string{"k": "v"},
+		Pages:  postgres.Int4Range(postgres.NewRange[int32](1, 10)),
+	}
+	if err := db.Create(&document).Error; err != nil {
+		t.Fatalf("no error should happen when create, got %v", err)
+	}
+
+	var found Document
+	if err := db.Where(postgres.Contains{Column: "tags", Value: []string{"db"}}).
+		Where(postgres.Overlaps{Column: "pages", Value: postgres.Int4Range(postgres.NewRange[int32](5, 20))}).
+		Where(postgres.Any{Column: "id", Values: []uint{document.ID}}).First(&found).Error; err != nil {
+		t.Fatalf("document should be found, got %v", err)
+	}
+
+	if !reflect.DeepEqual(found.Tags, document.Tags) || *found.Notes[0] != note || found.Notes[1] != nil || found.Attrs["k"] != "v" ||
+		found.Pages != document.Pages {
+		t.Errorf("values should be scanned back, expects %+v, got %+v", document, found)
+	}
+}
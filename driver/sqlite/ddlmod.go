@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/driver005/database/migrator"
+)
+
+var (
+	sqliteSeparator    = "`|\"|'|\t"
+	indexRegexp        = regexp.MustCompile(fmt.Sprintf("(?is)CREATE(?: UNIQUE)? INDEX [%v]?[\\w\\d-]+[%v]? ON (.*)$", sqliteSeparator, sqliteSeparator))
+	tableRegexp        = regexp.MustCompile(fmt.Sprintf("(?is)(CREATE TABLE [%v]?[\\w\\d-]+[%v]?)(?: \\((.*)\\))?", sqliteSeparator, sqliteSeparator))
+	separatorRegexp    = regexp.MustCompile(fmt.Sprintf("[%v]", sqliteSeparator))
+	columnsRegexp      = regexp.MustCompile(fmt.Sprintf("[(,][%v]?(\\w+)[%v]?", sqliteSeparator, sqliteSeparator))
+	columnRegexp       = regexp.MustCompile(fmt.Sprintf("^[%v]?([\\w\\d]+)[%v]?\\s+([\\w\\(\\)\\d,\\s]+?)(\\s.*)?$", sqliteSeparator, sqliteSeparator))
+	fieldNameRegexp    = regexp.MustCompile(fmt.Sprintf("^[%v]?([\\w\\d]+)[%v]?\\s", sqliteSeparator, sqliteSeparator))
+	lengthRegexp       = regexp.MustCompile(`\((\d+)(?:\s*,\s*(\d+))?\)`)
+	defaultValueRegexp = regexp.MustCompile("(?i) DEFAULT \\(?(.+?)\\)?( COLLATE| GENERATED| CONSTRAINT| NOT| NULL| CHECK| UNIQUE| REFERENCES|$)")
+)
+
+// ddl parsed CREATE TABLE statement, fields are the column and table constraint definitions
+type ddl struct {
+	head    string
+	fields  []string
+	columns []migrator.ColumnType
+}
+
+// parseDDL parses CREATE TABLE and CREATE INDEX statements of sqlite_master
+func parseDDL(strs ...string) (*ddl, error) {
+	var result ddl
+	for _, str := range strs {
+		if sections := tableRegexp.FindStringSubmatch(str); len(sections) > 0 {
+			var (
+				body         = []rune(sections[2])
+				bracketLevel int
+				quote        rune
+				buf          string
+			)
+
+			result.head = sections[1]
+
+			for idx := 0; idx < len(body); idx++ {
+				var (
+					c    = body[idx]
+					next rune
+				)
+
+				if idx+1 < len(body) {
+					next = body[idx+1]
+				}
+
+				if separatorRegexp.MatchString(string(c)) {
+					if c == next {
+						// escaped quote
+						buf += string(c)
+						idx++
+					} else if quote == c {
+						quote = 0
+					} else if quote == 0 {
+						quote = c
+					}
+				} else if quote == 0 {
+					switch c {
+					case '(':
+						bracketLevel++
+					case ')':
+						bracketLevel--
+					case ',':
+						if bracketLevel == 0 {
+							result.fields = append(result.fields, strings.TrimSpace(buf))
+							buf = ""
+							continue
+						}
+					}
+				}
+
+				if bracketLevel < 0 {
+					return nil, errors.New("invalid DDL, unbalanced brackets")
+				}
+				buf += string(c)
+			}
+
+			if bracketLevel != 0 {
+				return nil, errors.New("invalid DDL, unbalanced brackets")
+			}
+
+			if buf != "" {
+				result.fields = append(result.fields, strings.TrimSpace(buf))
+			}
+
+			for _, f := range result.fields {
+				upper := strings.ToUpper(f)
+				if strings.HasPrefix(upper, "CHECK") || strings.HasPrefix(upper, "CONSTRAINT") ||
+					strings.HasPrefix(upper, "FOREIGN KEY") {
+					continue
+				}
+
+				if strings.HasPrefix(upper, "PRIMARY KEY") || strings.HasPrefix(upper, "UNIQUE") {
+					matches := columnsRegexp.FindAllStringSubmatch(f[strings.Index(f, "("):], -1)
+					for idx, column := range result.columns {
+						for _, match := range matches {
+							if column.NameValue.String != match[1] {
+								continue
+							}
+
+							if strings.HasPrefix(upper, "PRIMARY KEY") {
+								column.PrimaryKeyValue = sql.NullBool{Bool: true, Valid: true}
+							} else if len(matches) == 1 {
+								column.UniqueValue = sql.NullBool{Bool: true, Valid: true}
+							}
+							result.columns[idx] = column
+						}
+					}
+				} else if matches := columnRegexp.FindStringSubmatch(f); len(matches) > 0 {
+					columnType := migrator.ColumnType{
+						NameValue:         sql.NullString{String: matches[1], Valid: true},
+						DataTypeValue:     sql.NullString{String: matches[2], Valid: true},
+						ColumnTypeValue:   sql.NullString{String: matches[2], Valid: true},
+						PrimaryKeyValue:   sql.NullBool{Valid: true},
+						UniqueValue:       sql.NullBool{Valid: true},
+						NullableValue:     sql.NullBool{Bool: true, Valid: true},
+						DefaultValueValue: sql.NullString{Valid: false},
+					}
+
+					constraints := strings.ToUpper(matches[3])
+					if strings.Contains(constraints, " NOT NULL") {
+						columnType.NullableValue = sql.NullBool{Bool: false, Valid: true}
+					}
+
+					if strings.Contains(constraints, " UNIQUE") {
+						columnType.UniqueValue = sql.NullBool{Bool: true, Valid: true}
+					}
+
+					if strings.Contains(constraints, " PRIMARY KEY") {
+						columnType.PrimaryKeyValue = sql.NullBool{Bool: true, Valid: true}
+						columnType.AutoIncrementValue = sql.NullBool{Bool: strings.Contains(constraints, " AUTOINCREMENT"), Valid: true}
+					}
+
+					if defaultMatches := defaultValueRegexp.FindStringSubmatch(matches[3]); len(defaultMatches) > 1 {
+						if value := strings.Trim(defaultMatches[1], `"'`); !strings.EqualFold(value, "null") {
+							columnType.DefaultValueValue = sql.NullString{String: value, Valid: true}
+						}
+					}
+
+					// data type length, e.g: varchar(100), decimal(10,2)
+					if lengthMatches := lengthRegexp.FindStringSubmatch(columnType.DataTypeValue.String); len(lengthMatches) == 3 {
+						size, _ := strconv.Atoi(lengthMatches[1])
+						if lengthMatches[2] != "" {
+							scale, _ := strconv.Atoi(lengthMatches[2])
+							columnType.DecimalSizeValue = sql.NullInt64{Int64: int64(size), Valid: true}
+							columnType.ScaleValue = sql.NullInt64{Int64: int64(scale), Valid: true}
+						} else {
+							columnType.LengthValue = sql.NullInt64{Int64: int64(size), Valid: true}
+						}
+						columnType.DataTypeValue.String = strings.TrimSpace(strings.TrimSuffix(columnType.DataTypeValue.String, lengthMatches[0]))
+					}
+
+					result.columns = append(result.columns, columnType)
+				}
+			}
+		} else if indexRegexp.MatchString(str) {
+			// unique indexes aren't reported as unique columns
+		} else {
+			return nil, fmt.Errorf("invalid DDL %s", str)
+		}
+	}
+
+	return &result, nil
+}
+
+// compile builds the CREATE TABLE statement
+func (d *ddl) compile() string {
+	if len(d.fields) == 0 {
+		return d.head
+	}
+	return fmt.Sprintf("%s (%s)", d.head, strings.Join(d.fields, ","))
+}
+
+// lookUpField returns the index of the definition of column in fields
+func (d *ddl) lookUpField(name string) int {
+	for idx, f := range d.fields {
+		if matches := fieldNameRegexp.FindStringSubmatch(f); len(matches) > 0 && matches[1] == name {
+			upper := strings.ToUpper(f)
+			if !strings.HasPrefix(upper, "CONSTRAINT") && !strings.HasPrefix(upper, "CHECK") &&
+				!strings.HasPrefix(upper, "PRIMARY KEY") && !strings.HasPrefix(upper, "UNIQUE") &&
+				!strings.HasPrefix(upper, "FOREIGN KEY") {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+// constraintRegexp matches the definition of the named table constraint
+func constraintRegexp(name string) *regexp.Regexp {
+	return regexp.MustCompile("(?i)^CONSTRAINT [\"`]?" + regexp.QuoteMeta(name) + "[\"` ]")
+}
+
+// addConstraint adds the table constraint, or replaces the one with the same name
+func (d *ddl) addConstraint(name string, sql string) {
+	reg := constraintRegexp(name)
+	for idx, f := range d.fields {
+		if reg.MatchString(f) {
+			d.fields[idx] = sql
+			return
+		}
+	}
+	d.fields = append(d.fields, sql)
+}
+
+// removeConstraint removes the named table constraint
+func (d *ddl) removeConstraint(name string) bool {
+	reg := constraintRegexp(name)
+	for idx, f := range d.fields {
+		if reg.MatchString(f) {
+			d.fields = append(d.fields[:idx], d.fields[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// getColumns returns the quoted names of the stored columns
+func (d *ddl) getColumns() []string {
+	var columns []string
+	for _, column := range d.columns {
+		if idx := d.lookUpField(column.NameValue.String); idx >= 0 && !strings.Contains(strings.ToUpper(d.fields[idx]), "GENERATED ALWAYS AS") {
+			columns = append(columns, "`"+column.NameValue.String+"`")
+		}
+	}
+	return columns
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/driver005/database"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// extended result codes, https://www.sqlite.org/rescode.html
+var errCodes = map[int]error{
+	sqlite3.SQLITE_CONSTRAINT_UNIQUE:     database.ErrDuplicatedKey,
+	sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY: database.ErrDuplicatedKey,
+	sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY: database.ErrForeignKeyViolated,
+	sqlite3.SQLITE_CONSTRAINT_CHECK:      database.ErrCheckConstraintViolated,
+	sqlite3.SQLITE_CONSTRAINT_NOTNULL:    database.ErrNotNullViolated,
+}
+
+var (
+	// UNIQUE constraint failed: users.name, NOT NULL constraint failed: users.name
+	columnMatcher = regexp.MustCompile(`constraint failed: (\w+)\.`)
+	// CHECK constraint failed: chk_users_age
+	checkConstraintMatcher = regexp.MustCompile(`CHECK constraint failed: (\w+)`)
+)
+
+// Translate translate sqlite.Error to portable errors
+func (dialector Dialector) Translate(err error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	translatedErr, ok := errCodes[sqliteErr.Code()]
+	if !ok {
+		// primary result codes are the least significant 8 bits of extended result codes
+		if code := sqliteErr.Code() & 0xff; code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED {
+			return &database.TranslatedError{Err: database.ErrLockTimeout, Cause: err}
+		}
+		return err
+	}
+
+	result := &database.TranslatedError{Err: translatedErr, Cause: err}
+	switch translatedErr {
+	case database.ErrCheckConstraintViolated:
+		if matches := checkConstraintMatcher.FindStringSubmatch(sqliteErr.Error()); len(matches) == 2 {
+			result.Constraint = matches[1]
+		}
+	case database.ErrDuplicatedKey, database.ErrNotNullViolated:
+		if matches := columnMatcher.FindStringSubmatch(sqliteErr.Error()); len(matches) == 2 {
+			result.Table = matches[1]
+		}
+	}
+	return result
+}
@@ -0,0 +1,551 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/migrator"
+	"github.com/driver005/database/schema"
+)
+
+var typeAliasMap = map[string][]string{
+	"int":     {"integer"},
+	"integer": {"int"},
+	"bool":    {"numeric"},
+	"numeric": {"bool"},
+}
+
+// ErrRebuildInTransaction tables can't be rebuilt within transactions while foreign keys are enforced, the pragma
+// foreign_keys is a no-op within transactions, so dropping the old table would cascade or fail foreign key checks,
+// rebuild tables without transaction, e.g: set DisableTransaction of migrations
+var ErrRebuildInTransaction = errors.New("sqlite: can't rebuild tables within transactions while foreign keys are enforced")
+
+// Migrator SQLite can't alter or drop columns and constraints in place, those tables are rebuilt instead
+type Migrator struct {
+	migrator.Migrator
+}
+
+// withoutForeignKey runs fc with foreign key enforcement disabled, otherwise dropping the old table while rebuilding
+// it would cascade. The pragma is per connection and is a no-op within transactions, so a connection is pinned when
+// the migrator runs on the pool and fc runs as is within transactions
+func (m Migrator) withoutForeignKey(fc func(m Migrator) error) error {
+	switch m.DB.Statement.ConnPool.(type) {
+	case *sql.DB:
+		return m.DB.Connection(func(tx *database.DB) error {
+			config := m.Config
+			config.DB = tx.Session(&database.Session{})
+			return Migrator{migrator.Migrator{Config: config}}.withoutForeignKey(fc)
+		})
+	case *sql.Conn:
+		var enabled int
+		if err := m.DB.Raw("PRAGMA foreign_keys").Row().Scan(&enabled); err != nil {
+			return err
+		}
+
+		if enabled == 1 {
+			if err := m.DB.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+				return err
+			}
+			defer m.DB.Exec("PRAGMA foreign_keys = ON")
+		}
+	}
+
+	return fc(m)
+}
+
+// rebuildWithoutForeignKey runs fc, which rebuilds tables, with foreign key enforcement disabled, see withoutForeignKey
+func (m Migrator) rebuildWithoutForeignKey(fc func(m Migrator) error) error {
+	if _, ok := m.DB.Statement.ConnPool.(database.TxCommitter); ok {
+		var enabled int
+		if err := m.DB.Raw("PRAGMA foreign_keys").Row().Scan(&enabled); err != nil {
+			return err
+		}
+
+		if enabled == 1 {
+			return ErrRebuildInTransaction
+		}
+	}
+
+	return m.withoutForeignKey(fc)
+}
+
+func (m Migrator) HasTable(value interface{}) bool {
+	var count int
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		return m.DB.Raw("SELECT count(*) FROM sqlite_master WHERE type = ? AND name = ?", "table", stmt.Table).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+func (m Migrator) DropTable(values ...interface{}) error {
+	return m.withoutForeignKey(func(m Migrator) error {
+		values = m.ReorderModels(values, false)
+		tx := m.DB.Session(&database.Session{})
+
+		for i := len(values) - 1; i >= 0; i-- {
+			if err := m.RunWithValue(values[i], func(stmt *database.Statement) error {
+				return tx.Exec("DROP TABLE IF EXISTS ?", clause.Table{Name: stmt.Table}).Error
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m Migrator) GetTables() (tableList []string, err error) {
+	return tableList, m.DB.Raw("SELECT name FROM sqlite_master WHERE type = ? AND name NOT LIKE ?", "table", "sqlite_%").
+		Scan(&tableList).Error
+}
+
+func (m Migrator) HasColumn(value interface{}, name string) bool {
+	var count int
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		if field := stmt.Schema.LookUpField(name); field != nil {
+			name = field.DBName
+		}
+
+		if name != "" {
+			m.DB.Raw(
+				"SELECT count(*) FROM sqlite_master WHERE type = ? AND tbl_name = ? AND (sql LIKE ? OR sql LIKE ? OR sql LIKE ? OR sql LIKE ? OR sql LIKE ?)",
+				"table", stmt.Table, `%"`+name+`" %`, `%`+name+` %`, "%`"+name+"`%", "%["+name+"]%", "%\t"+name+"\t%",
+			).Row().Scan(&count)
+		}
+		return nil
+	})
+	return count > 0
+}
+
+func (m Migrator) AlterColumn(value interface{}, name string) error {
+	return m.rebuildWithoutForeignKey(func(m Migrator) error {
+		return m.recreateTable(value, nil, func(ddl *ddl, stmt *database.Statement) (*ddl, []interface{}, error) {
+			field := stmt.Schema.LookUpField(name)
+			if field == nil {
+				return nil, nil, fmt.Errorf("failed to look up field with name: %s", name)
+			}
+
+			idx := ddl.lookUpField(field.DBName)
+			if idx < 0 {
+				return nil, nil, fmt.Errorf("failed to alter field with name %s", name)
+			}
+
+			ddl.fields[idx] = fmt.Sprintf("`%s` ?", field.DBName)
+			return ddl, []interface{}{m.FullDataTypeOf(field)}, nil
+		})
+	})
+}
+
+func (m Migrator) DropColumn(value interface{}, name string) error {
+	return m.rebuildWithoutForeignKey(func(m Migrator) error {
+		return m.recreateTable(value, nil, func(ddl *ddl, stmt *database.Statement) (*ddl, []interface{}, error) {
+			if field := stmt.Schema.LookUpField(name); field != nil {
+				name = field.DBName
+			}
+
+			idx := ddl.lookUpField(name)
+			if idx < 0 {
+				return nil, nil, fmt.Errorf("failed to drop field with name %s", name)
+			}
+
+			ddl.fields = append(ddl.fields[:idx], ddl.fields[idx+1:]...)
+			for i, column := range ddl.columns {
+				if column.NameValue.String == name {
+					ddl.columns = append(ddl.columns[:i], ddl.columns[i+1:]...)
+					break
+				}
+			}
+			return ddl, nil, nil
+		})
+	})
+}
+
+func (m Migrator) ColumnTypes(value interface{}) ([]database.ColumnType, error) {
+	columnTypes := make([]database.ColumnType, 0)
+	execErr := m.RunWithValue(value, func(stmt *database.Statement) (err error) {
+		var (
+			sqls   []string
+			sqlDDL *ddl
+		)
+
+		if err := m.DB.Raw("SELECT sql FROM sqlite_master WHERE type IN ? AND tbl_name = ? AND sql IS NOT NULL ORDER BY type = ? DESC",
+			[]string{"table", "index"}, stmt.Table, "table").Scan(&sqls).Error; err != nil {
+			return err
+		}
+
+		if sqlDDL, err = parseDDL(sqls...); err != nil {
+			return err
+		}
+
+		rows, err := m.DB.Session(&database.Session{}).Table(stmt.Table).Limit(1).Rows()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = rows.Close()
+		}()
+
+		var rawColumnTypes []*sql.ColumnType
+		rawColumnTypes, err = rows.ColumnTypes()
+		if err != nil {
+			return err
+		}
+
+		for _, c := range rawColumnTypes {
+			columnType := migrator.ColumnType{SQLColumnType: c}
+			for _, column := range sqlDDL.columns {
+				if column.NameValue.String == c.Name() {
+					column.SQLColumnType = c
+					columnType = column
+					break
+				}
+			}
+			columnTypes = append(columnTypes, columnType)
+		}
+
+		return err
+	})
+
+	return columnTypes, execErr
+}
+
+// buildConstraint builds the FOREIGN KEY table constraint
+func buildConstraint(constraint *schema.Constraint) (sql string, results []interface{}) {
+	sql = "CONSTRAINT ? FOREIGN KEY ? REFERENCES ??"
+	if constraint.OnDelete != "" {
+		sql += " ON DELETE " + constraint.OnDelete
+	}
+
+	if constraint.OnUpdate != "" {
+		sql += " ON UPDATE " + constraint.OnUpdate
+	}
+
+	var foreignKeys, references []interface{}
+	for _, field := range constraint.ForeignKeys {
+		foreignKeys = append(foreignKeys, clause.Column{Name: field.DBName})
+	}
+
+	for _, field := range constraint.References {
+		references = append(references, clause.Column{Name: field.DBName})
+	}
+	results = append(results, clause.Table{Name: constraint.Name}, foreignKeys, clause.Table{Name: constraint.ReferenceSchema.Table}, references)
+	return
+}
+
+func (m Migrator) CreateConstraint(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
+
+		var (
+			constraintName string
+			constraintSQL  string
+			vars           []interface{}
+		)
+
+		switch {
+		case chk != nil:
+			constraintName, constraintSQL = chk.Name, "CONSTRAINT ? CHECK (?)"
+			vars = []interface{}{clause.Column{Name: chk.Name}, clause.Expr{SQL: chk.Constraint}}
+		case constraint != nil:
+			constraintName = constraint.Name
+			constraintSQL, vars = buildConstraint(constraint)
+		default:
+			return nil
+		}
+
+		return m.rebuildWithoutForeignKey(func(m Migrator) error {
+			return m.recreateTable(value, &table, func(ddl *ddl, stmt *database.Statement) (*ddl, []interface{}, error) {
+				ddl.addConstraint(constraintName, "?")
+				return ddl, []interface{}{clause.Expr{SQL: constraintSQL, Vars: vars}}, nil
+			})
+		})
+	})
+}
+
+func (m Migrator) DropConstraint(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
+		if constraint != nil {
+			name = constraint.Name
+		} else if chk != nil {
+			name = chk.Name
+		}
+
+		return m.rebuildWithoutForeignKey(func(m Migrator) error {
+			return m.recreateTable(value, &table, func(ddl *ddl, stmt *database.Statement) (*ddl, []interface{}, error) {
+				ddl.removeConstraint(name)
+				return ddl, nil, nil
+			})
+		})
+	})
+}
+
+func (m Migrator) HasConstraint(value interface{}, name string) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
+		if constraint != nil {
+			name = constraint.Name
+		} else if chk != nil {
+			name = chk.Name
+		}
+
+		m.DB.Raw(
+			"SELECT count(*) FROM sqlite_master WHERE type = ? AND tbl_name = ? AND (sql LIKE ? OR sql LIKE ? OR sql LIKE ? OR sql LIKE ? OR sql LIKE ?)",
+			"table", table, `%CONSTRAINT "`+name+`" %`, `%CONSTRAINT `+name+` %`, "%CONSTRAINT `"+name+"`%", "%CONSTRAINT ["+name+"]%", "%CONSTRAINT \t"+name+"\t%",
+		).Row().Scan(&count)
+		return nil
+	})
+	return count > 0
+}
+
+func (m Migrator) CurrentDatabase() (name string) {
+	var null interface{}
+	m.DB.Raw("PRAGMA database_list").Row().Scan(&null, &name, &null)
+	return
+}
+
+func (m Migrator) BuildIndexOptions(opts []schema.IndexOption, stmt *database.Statement) (results []interface{}) {
+	for _, opt := range opts {
+		str := stmt.Quote(opt.DBName)
+		if opt.Expression != "" {
+			str = opt.Expression
+		}
+
+		if opt.Collate != "" {
+			str += " COLLATE " + opt.Collate
+		}
+
+		if opt.Sort != "" {
+			str += " " + opt.Sort
+		}
+		results = append(results, clause.Expr{SQL: str})
+	}
+	return
+}
+
+func (m Migrator) CreateIndex(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			opts := m.BuildIndexOptions(idx.Fields, stmt)
+			values := []interface{}{clause.Column{Name: idx.Name}, clause.Table{Name: stmt.Table}, opts}
+
+			createIndexSQL := "CREATE "
+			if idx.Class != "" {
+				createIndexSQL += idx.Class + " "
+			}
+			createIndexSQL += "INDEX ? ON ??"
+
+			if idx.Where != "" {
+				createIndexSQL += " WHERE " + idx.Where
+			}
+
+			return m.DB.Exec(createIndexSQL, values...).Error
+		}
+
+		return fmt.Errorf("failed to create index with name %s", name)
+	})
+}
+
+func (m Migrator) HasIndex(value interface{}, name string) bool {
+	var count int
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+
+		if name != "" {
+			m.DB.Raw(
+				"SELECT count(*) FROM sqlite_master WHERE type = ? AND tbl_name = ? AND name = ?", "index", stmt.Table, name,
+			).Row().Scan(&count)
+		}
+		return nil
+	})
+	return count > 0
+}
+
+func (m Migrator) RenameIndex(value interface{}, oldName, newName string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		var sql string
+		m.DB.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND tbl_name = ? AND name = ?", "index", stmt.Table, oldName).Row().Scan(&sql)
+		if sql != "" {
+			if err := m.DropIndex(value, oldName); err != nil {
+				return err
+			}
+			return m.DB.Exec(strings.Replace(sql, oldName, newName, 1)).Error
+		}
+		return fmt.Errorf("failed to find index with name %v", oldName)
+	})
+}
+
+func (m Migrator) DropIndex(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+
+		return m.DB.Exec("DROP INDEX ?", clause.Column{Name: name}).Error
+	})
+}
+
+// GetIndexes returns indexes of value's table, indexes backing UNIQUE constraints are skipped
+func (m Migrator) GetIndexes(value interface{}) ([]database.Index, error) {
+	indexes := make([]database.Index, 0)
+	err := m.RunWithValue(value, func(stmt *database.Statement) error {
+		type indexInfo struct {
+			Seq     int
+			Name    string
+			Unique  bool
+			Origin  string
+			Partial bool
+		}
+
+		var infos []indexInfo
+		if err := m.DB.Raw("SELECT seq, name, `unique`, origin, partial FROM pragma_index_list(?)", stmt.Table).
+			Scan(&infos).Error; err != nil {
+			return err
+		}
+
+		for _, info := range infos {
+			if info.Origin == "u" {
+				continue
+			}
+
+			var columns []string
+			if err := m.DB.Raw("SELECT name FROM pragma_index_info(?) ORDER BY seqno", info.Name).
+				Scan(&columns).Error; err != nil {
+				return err
+			}
+
+			indexes = append(indexes, &migrator.Index{
+				TableName:       stmt.Table,
+				NameValue:       info.Name,
+				ColumnList:      columns,
+				PrimaryKeyValue: sql.NullBool{Bool: info.Origin == "pk", Valid: true},
+				UniqueValue:     sql.NullBool{Bool: info.Unique, Valid: true},
+			})
+		}
+		return nil
+	})
+	return indexes, err
+}
+
+func (m Migrator) HasView(name string) bool {
+	var count int64
+	m.DB.Raw("SELECT count(*) FROM sqlite_master WHERE type = ? AND name = ?", "view", name).Row().Scan(&count)
+	return count > 0
+}
+
+// CreateView SQLite doesn't support CREATE OR REPLACE VIEW, the view is dropped first when Replace is set
+func (m Migrator) CreateView(name string, option database.ViewOption) error {
+	if option.CheckOption != "" {
+		return errors.New("sqlite doesn't support view check option")
+	}
+
+	if option.Replace {
+		if err := m.DropView(name); err != nil {
+			return err
+		}
+		option.Replace = false
+	}
+	return m.Migrator.CreateView(name, option)
+}
+
+func (m Migrator) GetTypeAliases(databaseTypeName string) []string {
+	return typeAliasMap[databaseTypeName]
+}
+
+// recreateTable rebuilds the table from the DDL returned by fc, rows and indexes are copied over
+func (m Migrator) recreateTable(
+	value interface{}, tablePtr *string,
+	fc func(ddl *ddl, stmt *database.Statement) (*ddl, []interface{}, error),
+) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		table := stmt.Table
+		if tablePtr != nil {
+			table = *tablePtr
+		}
+
+		var (
+			createSQL string
+			indexSQLs []string
+		)
+		if err := m.DB.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND tbl_name = ? AND name = ?", "table", table, table).
+			Row().Scan(&createSQL); err != nil {
+			return err
+		}
+
+		if err := m.DB.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND tbl_name = ? AND sql IS NOT NULL", "index", table).
+			Scan(&indexSQLs).Error; err != nil {
+			return err
+		}
+
+		originDDL, err := parseDDL(createSQL)
+		if err != nil {
+			return err
+		}
+
+		newDDL, vars, err := fc(originDDL, stmt)
+		if err != nil {
+			return err
+		}
+
+		newTableName := table + "__temp"
+		columns := strings.Join(newDDL.getColumns(), ",")
+		createTableSQL := strings.Replace(newDDL.compile(), table, newTableName, 1)
+
+		return m.transaction(func(tx *database.DB) error {
+			if err := tx.Exec(createTableSQL, vars...).Error; err != nil {
+				return err
+			}
+
+			queries := []string{
+				fmt.Sprintf("INSERT INTO `%v`(%v) SELECT %v FROM `%v`", newTableName, columns, columns, table),
+				fmt.Sprintf("DROP TABLE `%v`", table),
+				fmt.Sprintf("ALTER TABLE `%v` RENAME TO `%v`", newTableName, table),
+			}
+
+			// indexes are dropped with the old table
+			for _, indexSQL := range indexSQLs {
+				if matches := indexRegexp.FindStringSubmatch(indexSQL); len(matches) > 1 {
+					if columnsRegexp.MatchString(matches[1]) && !m.indexOnDroppedColumn(newDDL, matches[1]) {
+						queries = append(queries, indexSQL)
+					}
+				}
+			}
+
+			for _, query := range queries {
+				if err := tx.Exec(query).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// indexOnDroppedColumn reports whether the index definition `table`(columns) references a column missing in ddl
+func (m Migrator) indexOnDroppedColumn(ddl *ddl, definition string) bool {
+	if start := strings.Index(definition, "("); start >= 0 {
+		for _, match := range columnsRegexp.FindAllStringSubmatch(definition[start:], -1) {
+			if ddl.lookUpField(match[1]) < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transaction runs fc in a transaction, or a savepoint when the migrator runs in one already, statements are run as
+// is by other pools, e.g. when planning migrations
+func (m Migrator) transaction(fc func(tx *database.DB) error) error {
+	switch m.DB.Statement.ConnPool.(type) {
+	case database.TxBeginner, database.ConnPoolBeginner, database.TxCommitter:
+		return m.DB.Transaction(fc)
+	}
+	return fc(m.DB)
+}
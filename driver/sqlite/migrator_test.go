@@ -0,0 +1,206 @@
+package sqlite_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/schema"
+)
+
+type CompanyWithCode struct {
+	ID   uint
+	Name string `database:"size:64;uniqueIndex"`
+	Code string
+}
+
+func (CompanyWithCode) TableName() string {
+	return "companies"
+}
+
+type UserWithNullableName struct {
+	ID        uint
+	Name      string `database:"size:32;index"`
+	Age       int    `database:"default:18;check:chk_users_age,age > 0"`
+	CompanyID *uint
+	Company   *Company `database:"constraint:OnDelete:CASCADE"`
+}
+
+func (UserWithNullableName) TableName() string {
+	return "users"
+}
+
+func seed(t *testing.T, db *database.DB) Company {
+	company := Company{Name: "company"}
+	if err := db.Create(&company).Error; err != nil {
+		t.Fatalf("no error should happen when create company, got %v", err)
+	}
+
+	if err := db.Create(&User{Name: "jinzhu", Age: 20, CompanyID: &company.ID}).Error; err != nil {
+		t.Fatalf("no error should happen when create user, got %v", err)
+	}
+	return company
+}
+
+func TestAlterColumn(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+	company := seed(t, db)
+
+	if err := db.Migrator().AlterColumn(&UserWithNullableName{}, "Name"); err != nil {
+		t.Fatalf("no error should happen when alter column, got %v", err)
+	}
+
+	columnTypes, err := db.Migrator().ColumnTypes(&UserWithNullableName{})
+	if err != nil {
+		t.Fatalf("no error should happen when get column types, got %v", err)
+	}
+
+	for _, columnType := range columnTypes {
+		if columnType.Name() == "name" {
+			if nullable, _ := columnType.Nullable(); !nullable {
+				t.Errorf("column name should be altered to nullable")
+			}
+		}
+	}
+
+	var user User
+	if err := db.First(&user, "name = ?", "jinzhu").Error; err != nil || user.Age != 20 {
+		t.Errorf("rows should be kept after rebuild, got %+v, error %v", user, err)
+	}
+
+	for _, name := range []string{"Name", "chk_users_age", "Company"} {
+		if !db.Migrator().HasIndex(&User{}, name) && !db.Migrator().HasConstraint(&User{}, name) {
+			t.Errorf("index or constraint %v should be kept after rebuild", name)
+		}
+	}
+
+	// foreign keys are still enforced and cascaded
+	if err := db.Delete(&company).Error; err != nil {
+		t.Fatalf("no error should happen when delete company, got %v", err)
+	}
+
+	var count int64
+	if db.Model(&User{}).Count(&count); count != 0 {
+		t.Errorf("users should be deleted in cascade, got %v", count)
+	}
+}
+
+func TestRebuildReferencedTable(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+	seed(t, db)
+
+	if err := db.Migrator().AddColumn(&CompanyWithCode{}, "Code"); err != nil {
+		t.Fatalf("no error should happen when add column, got %v", err)
+	}
+
+	// dropping the old companies table while rebuilding it must not cascade to users
+	if err := db.Migrator().DropColumn(&CompanyWithCode{}, "Code"); err != nil {
+		t.Fatalf("no error should happen when drop column, got %v", err)
+	}
+
+	if db.Migrator().HasColumn(&CompanyWithCode{}, "Code") {
+		t.Errorf("column code should be dropped")
+	}
+
+	var count int64
+	if db.Model(&User{}).Count(&count); count != 1 {
+		t.Errorf("users shouldn't be deleted when rebuild companies, got %v", count)
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+	seed(t, db)
+
+	for _, name := range []string{"chk_users_age", "Company"} {
+		if err := db.Migrator().DropConstraint(&User{}, name); err != nil {
+			t.Fatalf("no error should happen when drop constraint %v, got %v", name, err)
+		}
+
+		if db.Migrator().HasConstraint(&User{}, name) {
+			t.Errorf("constraint %v should be dropped", name)
+		}
+
+		if err := db.Migrator().CreateConstraint(&User{}, name); err != nil {
+			t.Fatalf("no error should happen when create constraint %v, got %v", name, err)
+		}
+
+		if !db.Migrator().HasConstraint(&User{}, name) {
+			t.Errorf("constraint %v should be created", name)
+		}
+	}
+
+	if err := db.Create(&User{Name: "jinzhu", Age: -1}).Error; !errors.Is(err, database.ErrCheckConstraintViolated) {
+		t.Errorf("check constraint should be enforced, got %v", err)
+	}
+}
+
+func TestRebuildInTransaction(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+	seed(t, db)
+
+	err := db.Transaction(func(tx *database.DB) error {
+		return tx.Migrator().AlterColumn(&UserWithNullableName{}, "Name")
+	})
+	if !errors.Is(err, sqlite.ErrRebuildInTransaction) {
+		t.Errorf("rebuild should be refused within transactions, got %v", err)
+	}
+
+	// tables are rebuilt within transactions when foreign keys aren't enforced
+	db = openDB(t, sqlite.Config{DSN: filepath.Join(t.TempDir(), "sqlite.db")})
+	seed(t, db)
+
+	err = db.Transaction(func(tx *database.DB) error {
+		return tx.Migrator().AlterColumn(&UserWithNullableName{}, "Name")
+	})
+	if err != nil {
+		t.Errorf("no error should happen when alter column, got %v", err)
+	}
+}
+
+func TestViews(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+	seed(t, db)
+
+	if err := db.Migrator().CreateView("adult_users", database.ViewOption{Query: db.Model(&User{}).Where("age > ?", 18)}); err != nil {
+		t.Fatalf("no error should happen when create view, got %v", err)
+	}
+
+	if !db.Migrator().HasView("adult_users") {
+		t.Errorf("view adult_users should be created")
+	}
+
+	var count int64
+	if db.Table("adult_users").Count(&count); count != 1 {
+		t.Errorf("view should be queried, got %v", count)
+	}
+
+	if err := db.Migrator().DropView("adult_users"); err != nil || db.Migrator().HasView("adult_users") {
+		t.Errorf("view adult_users should be dropped, got error %v", err)
+	}
+}
+
+type Article struct {
+	ID    uint
+	Title string
+	Tags  []string
+	Meta  map[string]string
+}
+
+func TestMigrateArrayAndMap(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+
+	if err := db.AutoMigrate(&Article{}); !errors.Is(err, schema.ErrUnsupportedDataType) {
+		t.Fatalf("arrays and maps should be unsupported without a dialect serializer, got %v", err)
+	}
+
+	if db.Migrator().HasTable(&Article{}) {
+		t.Fatalf("table articles should not be migrated")
+	}
+
+	if err := db.Create(&Article{Title: "article", Tags: []string{"go"}}).Error; !errors.Is(err, schema.ErrUnsupportedDataType) {
+		t.Fatalf("arrays and maps should be unsupported without a dialect serializer, got %v", err)
+	}
+}
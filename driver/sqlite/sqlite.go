@@ -0,0 +1,256 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/callbacks"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/migrator"
+	"github.com/driver005/database/schema"
+	_ "modernc.org/sqlite"
+)
+
+// DriverName the default driver name for SQLite, the pure Go driver modernc.org/sqlite, cgo isn't required
+const DriverName = "sqlite"
+
+type Config struct {
+	DriverName       string
+	DSN              string
+	WithoutReturning bool
+	// IgnoreLocking ignores locking clauses, e.g: FOR UPDATE, instead of failing with ErrUnsupportedDriver, SQLite
+	// locks the whole database while writing, so writes of transactions are serialized
+	IgnoreLocking bool
+	Conn          database.ConnPool
+}
+
+type Dialector struct {
+	*Config
+}
+
+func Open(dsn string) database.Dialector {
+	return &Dialector{&Config{DSN: dsn}}
+}
+
+func New(config Config) database.Dialector {
+	return &Dialector{Config: &config}
+}
+
+func (dialector Dialector) Name() string {
+	return "sqlite"
+}
+
+func (dialector Dialector) Initialize(db *database.DB) (err error) {
+	if dialector.DriverName == "" {
+		dialector.DriverName = DriverName
+	}
+
+	if dialector.Conn != nil {
+		db.ConnPool = dialector.Conn
+	} else {
+		db.ConnPool, err = sql.Open(dialector.DriverName, dialector.DSN)
+		if err != nil {
+			return err
+		}
+	}
+
+	callbackConfig := &callbacks.Config{
+		CreateClauses:        []string{"WITH", "INSERT", "VALUES", "ON CONFLICT"},
+		UpdateClauses:        []string{"WITH", "UPDATE", "SET", "WHERE"},
+		DeleteClauses:        []string{"WITH", "DELETE", "FROM", "WHERE"},
+		LastInsertIDReversed: true,
+	}
+
+	// RETURNING is supported since 3.35.0, https://www.sqlite.org/lang_returning.html
+	if !dialector.WithoutReturning {
+		var version string
+		if err = db.ConnPool.QueryRowContext(context.Background(), "SELECT sqlite_version()").Scan(&version); err != nil {
+			return err
+		}
+
+		if checkVersion(version, "3.35.0") {
+			callbackConfig.CreateClauses = append(callbackConfig.CreateClauses, "RETURNING")
+			callbackConfig.UpdateClauses = append(callbackConfig.UpdateClauses, "RETURNING")
+			callbackConfig.DeleteClauses = append(callbackConfig.DeleteClauses, "RETURNING")
+		}
+	}
+
+	callbacks.RegisterDefaultCallbacks(db, callbackConfig)
+
+	for k, v := range dialector.ClauseBuilders() {
+		db.ClauseBuilders[k] = v
+	}
+	return
+}
+
+const (
+	// ClauseLimit for clause.ClauseBuilder LIMIT key
+	ClauseLimit = "LIMIT"
+	// ClauseFor for clause.ClauseBuilder FOR key
+	ClauseFor = "FOR"
+)
+
+func (dialector Dialector) ClauseBuilders() map[string]clause.ClauseBuilder {
+	return map[string]clause.ClauseBuilder{
+		ClauseLimit: func(c clause.Clause, builder clause.Builder) {
+			limit, ok := c.Expression.(clause.Limit)
+			if !ok {
+				c.Build(builder)
+				return
+			}
+
+			// OFFSET requires LIMIT, a negative LIMIT means no upper bound
+			lmt := -1
+			if limit.Limit != nil && *limit.Limit >= 0 {
+				lmt = *limit.Limit
+			}
+
+			if lmt >= 0 || limit.Offset > 0 {
+				builder.WriteString("LIMIT ")
+				builder.WriteString(strconv.Itoa(lmt))
+			}
+
+			if limit.Offset > 0 {
+				builder.WriteString(" OFFSET ")
+				builder.WriteString(strconv.Itoa(limit.Offset))
+			}
+		},
+		ClauseFor: func(c clause.Clause, builder clause.Builder) {
+			// SQLite locks the whole database, row level locking isn't supported
+			if _, ok := c.Expression.(clause.Locking); ok {
+				if stmt, ok := builder.(*database.Statement); ok && !dialector.IgnoreLocking {
+					stmt.AddError(fmt.Errorf("%w: row locking isn't supported by sqlite", database.ErrUnsupportedDriver))
+				}
+				return
+			}
+			c.Build(builder)
+		},
+	}
+}
+
+// DefaultValueOf SQLite doesn't accept DEFAULT in VALUES, the default expression of the column is inlined instead
+func (dialector Dialector) DefaultValueOf(field *schema.Field) clause.Expression {
+	if !field.AutoIncrement && field.DefaultValue != "" {
+		return clause.Expr{SQL: field.DefaultValue}
+	}
+	return clause.Expr{SQL: "NULL"}
+}
+
+func (dialector Dialector) Migrator(db *database.DB) database.Migrator {
+	return Migrator{migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   dialector,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+func (dialector Dialector) BindVarTo(writer clause.Writer, stmt *database.Statement, v interface{}) {
+	writer.WriteByte('?')
+}
+
+func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
+	var (
+		underQuoted, selfQuoted bool
+		continuousBacktick      int8
+		shiftDelimiter          int8
+	)
+
+	for _, v := range []byte(str) {
+		switch v {
+		case '`':
+			continuousBacktick++
+			if continuousBacktick == 2 {
+				writer.WriteString("``")
+				continuousBacktick = 0
+			}
+		case '.':
+			if continuousBacktick > 0 || !selfQuoted {
+				shiftDelimiter = 0
+				underQuoted = false
+				continuousBacktick = 0
+				writer.WriteByte('`')
+			}
+			writer.WriteByte(v)
+			continue
+		default:
+			if shiftDelimiter-continuousBacktick <= 0 && !underQuoted {
+				writer.WriteByte('`')
+				underQuoted = true
+				if selfQuoted = continuousBacktick > 0; selfQuoted {
+					continuousBacktick -= 1
+				}
+			}
+
+			for ; continuousBacktick > 0; continuousBacktick -= 1 {
+				writer.WriteString("``")
+			}
+
+			writer.WriteByte(v)
+		}
+		shiftDelimiter++
+	}
+
+	if continuousBacktick > 0 && !selfQuoted {
+		writer.WriteString("``")
+	}
+	writer.WriteByte('`')
+}
+
+func (dialector Dialector) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, nil, `"`, vars...)
+}
+
+func (dialector Dialector) DataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "numeric"
+	case schema.Int, schema.Uint:
+		if field.AutoIncrement && !field.PrimaryKey {
+			// https://www.sqlite.org/autoinc.html
+			return "integer PRIMARY KEY AUTOINCREMENT"
+		}
+		return "integer"
+	case schema.Float:
+		return "real"
+	case schema.String:
+		return "text"
+	case schema.Time:
+		return "datetime"
+	case schema.Bytes:
+		return "blob"
+	case schema.JSON:
+		return "json"
+	}
+
+	return string(field.DataType)
+}
+
+func (dialector Dialector) SavePoint(tx *database.DB, name string) error {
+	return tx.Exec("SAVEPOINT " + name).Error
+}
+
+func (dialector Dialector) RollbackTo(tx *database.DB, name string) error {
+	return tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error
+}
+
+// checkVersion newer or equal returns true, old returns false
+func checkVersion(newVersion, oldVersion string) bool {
+	newVersions, oldVersions := strings.Split(newVersion, "."), strings.Split(oldVersion, ".")
+	for idx, nv := range newVersions {
+		if len(oldVersions) <= idx {
+			return true
+		}
+
+		nvi, _ := strconv.Atoi(nv)
+		ovi, _ := strconv.Atoi(oldVersions[idx])
+		if nvi != ovi {
+			return nvi > ovi
+		}
+	}
+	return len(newVersions) >= len(oldVersions)
+}
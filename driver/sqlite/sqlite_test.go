@@ -0,0 +1,228 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/logger"
+)
+
+type Company struct {
+	ID   uint
+	Name string `database:"size:64;uniqueIndex"`
+}
+
+type User struct {
+	ID        uint
+	Name      string `database:"size:32;not null;index"`
+	Age       int    `database:"default:18;check:chk_users_age,age > 0"`
+	CompanyID *uint
+	Company   *Company `database:"constraint:OnDelete:CASCADE"`
+}
+
+// recorder records SQL of executed statements, statements aren't executed in DryRun mode
+type recorder struct {
+	logger.Interface
+	sqls []string
+}
+
+func (r *recorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *recorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.sqls = append(r.sqls, sql)
+}
+
+func dsn(t *testing.T) string {
+	return filepath.Join(t.TempDir(), "sqlite.db") + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+func openDB(t *testing.T, config sqlite.Config) *database.DB {
+	if config.DSN == "" {
+		config.DSN = dsn(t)
+	}
+
+	db, err := database.Open(sqlite.New(config), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	if err := db.AutoMigrate(&Company{}, &User{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestCreateWithReturning(t *testing.T) {
+	for _, withoutReturning := range []bool{false, true} {
+		db := openDB(t, sqlite.Config{WithoutReturning: withoutReturning})
+
+		users := []User{{Name: "jinzhu"}, {Name: "jinzhu2", Age: 20}}
+		if err := db.Create(&users).Error; err != nil {
+			t.Fatalf("no error should happen when create users, got %v", err)
+		}
+
+		if users[0].ID == 0 || users[1].ID != users[0].ID+1 {
+			t.Errorf("primary keys should be set, got %v, %v", users[0].ID, users[1].ID)
+		}
+
+		if users[0].Age != 18 || users[1].Age != 20 {
+			t.Errorf("default values should be set, got %v, %v", users[0].Age, users[1].Age)
+		}
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+
+	company := Company{Name: "company"}
+	if err := db.Create(&company).Error; err != nil {
+		t.Fatalf("no error should happen when create company, got %v", err)
+	}
+
+	duplicated := Company{ID: company.ID, Name: "upserted"}
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&duplicated).Error; err != nil {
+		t.Fatalf("no error should happen when upsert company, got %v", err)
+	}
+
+	var result Company
+	if err := db.First(&result, company.ID).Error; err != nil || result.Name != "upserted" {
+		t.Errorf("company should be upserted, got %+v, error %v", result, err)
+	}
+}
+
+func TestLimitAndOffset(t *testing.T) {
+	r := &recorder{Interface: logger.Discard}
+	db, err := database.Open(sqlite.Open(dsn(t)), &database.Config{DryRun: true, Logger: r})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	results := []struct {
+		Name  string
+		Query func(db *database.DB) *database.DB
+		SQL   string
+	}{
+		{"Limit", func(db *database.DB) *database.DB { return db.Limit(10) }, "SELECT * FROM `users` LIMIT 10"},
+		{"Offset", func(db *database.DB) *database.DB { return db.Offset(5) }, "SELECT * FROM `users` LIMIT -1 OFFSET 5"},
+		{"LimitAndOffset", func(db *database.DB) *database.DB { return db.Limit(10).Offset(5) }, "SELECT * FROM `users` LIMIT 10 OFFSET 5"},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			r.sqls = nil
+			var users []User
+			if err := result.Query(db).Find(&users).Error; err != nil {
+				t.Fatalf("no error should happen, got %v", err)
+			}
+
+			if len(r.sqls) != 1 || r.sqls[0] != result.SQL {
+				t.Errorf("SQL expects %v got %v", result.SQL, r.sqls)
+			}
+		})
+	}
+}
+
+func TestLocking(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+
+	var users []User
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&users).Error
+	if !errors.Is(err, database.ErrUnsupportedDriver) {
+		t.Errorf("locking should be rejected, got %v", err)
+	}
+
+	db = openDB(t, sqlite.Config{IgnoreLocking: true})
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&users).Error; err != nil {
+		t.Errorf("locking should be ignored, got %v", err)
+	}
+}
+
+func TestSavePoint(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+
+	err := db.Transaction(func(tx *database.DB) error {
+		if err := tx.Create(&User{Name: "outer"}).Error; err != nil {
+			return err
+		}
+
+		tx.Transaction(func(tx *database.DB) error {
+			tx.Create(&User{Name: "inner"})
+			return errors.New("rollback inner")
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("no error should happen, got %v", err)
+	}
+
+	var names []string
+	db.Model(&User{}).Order("id").Pluck("name", &names)
+	if len(names) != 1 || names[0] != "outer" {
+		t.Errorf("inner transaction should be rolled back to the savepoint, got %v", names)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	db := openDB(t, sqlite.Config{})
+
+	company := Company{Name: "company"}
+	if err := db.Create(&company).Error; err != nil {
+		t.Fatalf("no error should happen when create company, got %v", err)
+	}
+
+	missing := uint(100)
+	results := []struct {
+		Name   string
+		Create func(db *database.DB) error
+		Err    error
+	}{
+		{"DuplicatedKey", func(db *database.DB) error {
+			return db.Create(&Company{Name: "company"}).Error
+		}, database.ErrDuplicatedKey},
+		{"DuplicatedPrimaryKey", func(db *database.DB) error {
+			return db.Create(&Company{ID: company.ID, Name: "other"}).Error
+		}, database.ErrDuplicatedKey},
+		{"ForeignKeyViolated", func(db *database.DB) error {
+			return db.Create(&User{Name: "jinzhu", CompanyID: &missing}).Error
+		}, database.ErrForeignKeyViolated},
+		{"CheckConstraintViolated", func(db *database.DB) error {
+			return db.Create(&User{Name: "jinzhu", Age: -1}).Error
+		}, database.ErrCheckConstraintViolated},
+		{"NotNullViolated", func(db *database.DB) error {
+			return db.Model(&User{}).Create(map[string]interface{}{"age": 20}).Error
+		}, database.ErrNotNullViolated},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			err := result.Create(db)
+			if !errors.Is(err, result.Err) {
+				t.Fatalf("expects %v, got %v", result.Err, err)
+			}
+
+			var translated *database.TranslatedError
+			if !errors.As(err, &translated) {
+				t.Fatalf("expects TranslatedError, got %T", err)
+			}
+
+			if result.Err == database.ErrCheckConstraintViolated && translated.Constraint != "chk_users_age" {
+				t.Errorf("constraint expects chk_users_age, got %v", translated.Constraint)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package sqlserver
+
+import (
+	"github.com/driver005/database"
+	"github.com/driver005/database/callbacks"
+	"github.com/driver005/database/clause"
+)
+
+// Create create callback, values of default value fields are returned with OUTPUT INSERTED and ON CONFLICT is
+// converted to MERGE
+func Create(db *database.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	if db.Statement.Schema != nil && !db.Statement.Unscoped {
+		for _, c := range db.Statement.Schema.CreateClauses {
+			db.Statement.AddClause(c)
+		}
+	}
+
+	var (
+		hasOutput bool
+		mode      database.ScanMode
+	)
+
+	if db.Statement.SQL.Len() == 0 {
+		db.Statement.SQL.Grow(180)
+		db.Statement.AddClauseIfNotExists(clause.Insert{})
+		values := callbacks.ConvertToCreateValues(db.Statement)
+
+		// identity columns only accept explicit values with IDENTITY_INSERT on
+		identityInsert := false
+		if db.Statement.Schema != nil {
+			if field := db.Statement.Schema.PrioritizedPrimaryField; field != nil && field.AutoIncrement {
+				for _, column := range values.Columns {
+					if column.Name == field.DBName {
+						identityInsert = true
+						break
+					}
+				}
+			}
+		}
+
+		if identityInsert {
+			db.Statement.WriteString("SET IDENTITY_INSERT ")
+			db.Statement.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+			db.Statement.WriteString(" ON;")
+		}
+
+		if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok && len(values.Columns) > 0 {
+			onConflict, _ := c.Expression.(clause.OnConflict)
+			hasOutput, mode = mergeCreate(db, onConflict, values)
+			if onConflict.DoNothing {
+				mode |= database.ScanOnConflictDoNothing
+			}
+		} else {
+			db.Statement.Build("WITH", "INSERT")
+			db.Statement.WriteByte(' ')
+
+			if len(values.Columns) > 0 {
+				db.Statement.WriteByte('(')
+				for idx, column := range values.Columns {
+					if idx > 0 {
+						db.Statement.WriteByte(',')
+					}
+					db.Statement.WriteQuoted(column)
+				}
+				db.Statement.WriteByte(')')
+
+				hasOutput, mode = outputInserted(db)
+				db.Statement.WriteString(" VALUES ")
+
+				for idx, value := range values.Values {
+					if idx > 0 {
+						db.Statement.WriteByte(',')
+					}
+
+					db.Statement.WriteByte('(')
+					db.Statement.AddVar(db.Statement, value...)
+					db.Statement.WriteByte(')')
+				}
+			} else {
+				hasOutput, mode = outputInserted(db)
+				db.Statement.WriteString(" DEFAULT VALUES")
+			}
+			db.Statement.WriteByte(';')
+		}
+
+		if identityInsert {
+			db.Statement.WriteString("SET IDENTITY_INSERT ")
+			db.Statement.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+			db.Statement.WriteString(" OFF;")
+		}
+	}
+
+	if db.DryRun || db.Error != nil {
+		return
+	}
+
+	if hasOutput {
+		rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+		if db.AddError(err) == nil {
+			defer func() {
+				db.AddError(rows.Close())
+			}()
+			database.Scan(rows, db, mode)
+		}
+		return
+	}
+
+	result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if db.AddError(err) == nil {
+		db.RowsAffected, _ = result.RowsAffected()
+	}
+}
+
+// mergeCreate writes MERGE statement of values, rows matching onConflict's columns, primary keys by default, are
+// updated with DoUpdates
+//
+//	MERGE INTO [users] WITH (HOLDLOCK) USING (VALUES (@p1,@p2)) AS excluded ([id],[name]) ON [users].[id] = [excluded].[id]
+//	WHEN MATCHED THEN UPDATE SET [name]=[excluded].[name]
+//	WHEN NOT MATCHED THEN INSERT ([id],[name]) VALUES ([excluded].[id],[excluded].[name]) OUTPUT INSERTED.[id];
+func mergeCreate(db *database.DB, onConflict clause.OnConflict, values clause.Values) (hasOutput bool, mode database.ScanMode) {
+	columns := onConflict.Columns
+	if len(columns) == 0 && db.Statement.Schema != nil {
+		for _, field := range db.Statement.Schema.PrimaryFields {
+			columns = append(columns, clause.Column{Name: field.DBName})
+		}
+	}
+
+	if len(columns) == 0 {
+		db.AddError(database.ErrPrimaryKeyRequired)
+		return
+	}
+
+	db.Statement.WriteString("MERGE INTO ")
+	db.Statement.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+	db.Statement.WriteString(" WITH (HOLDLOCK) USING (VALUES ")
+	for idx, value := range values.Values {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+
+		db.Statement.WriteByte('(')
+		db.Statement.AddVar(db.Statement, value...)
+		db.Statement.WriteByte(')')
+	}
+
+	db.Statement.WriteString(") AS excluded (")
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(column.Name)
+	}
+
+	db.Statement.WriteString(") ON ")
+	for idx, column := range columns {
+		if idx > 0 {
+			db.Statement.WriteString(" AND ")
+		}
+		db.Statement.WriteQuoted(clause.Column{Table: clause.CurrentTable, Name: column.Name})
+		db.Statement.WriteString(" = ")
+		db.Statement.WriteQuoted(clause.Column{Table: "excluded", Name: column.Name})
+	}
+
+	if !onConflict.DoNothing && len(onConflict.DoUpdates) > 0 {
+		db.Statement.WriteString(" WHEN MATCHED")
+		if len(onConflict.Where.Exprs) > 0 {
+			db.Statement.WriteString(" AND ")
+			onConflict.Where.Build(db.Statement)
+		}
+		db.Statement.WriteString(" THEN UPDATE SET ")
+		onConflict.DoUpdates.Build(db.Statement)
+	}
+
+	db.Statement.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(column.Name)
+	}
+
+	db.Statement.WriteString(") VALUES (")
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			db.Statement.WriteByte(',')
+		}
+		db.Statement.WriteQuoted(clause.Column{Table: "excluded", Name: column.Name})
+	}
+	db.Statement.WriteByte(')')
+
+	hasOutput, mode = outputInserted(db)
+	db.Statement.WriteByte(';')
+	return
+}
+
+// outputInserted writes OUTPUT INSERTED of the RETURNING clause, or of fields with default database values
+func outputInserted(db *database.DB) (bool, database.ScanMode) {
+	var (
+		columns []clause.Column
+		mode    = database.ScanUpdate
+	)
+
+	if c, ok := db.Statement.Clauses["RETURNING"]; ok {
+		returning, _ := c.Expression.(clause.Returning)
+		if columns = returning.Columns; len(columns) == 0 || (len(columns) == 1 && columns[0].Name == "*") {
+			mode = 0
+		}
+	} else if db.Statement.Schema != nil && len(db.Statement.Schema.FieldsWithDefaultDBValue) > 0 {
+		for _, field := range db.Statement.Schema.FieldsWithDefaultDBValue {
+			columns = append(columns, clause.Column{Name: field.DBName})
+		}
+	} else {
+		return false, 0
+	}
+
+	db.Statement.WriteByte(' ')
+	writeOutput(db.Statement, "INSERTED", columns)
+	return true, mode
+}
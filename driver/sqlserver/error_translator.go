@@ -0,0 +1,66 @@
+package sqlserver
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/driver005/database"
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+// https://learn.microsoft.com/en-us/sql/relational-databases/errors-events/database-engine-events-and-errors
+var errCodes = map[int32]error{
+	2627: database.ErrDuplicatedKey,
+	2601: database.ErrDuplicatedKey,
+	547:  database.ErrForeignKeyViolated,
+	515:  database.ErrNotNullViolated,
+	1205: database.ErrDeadlock,
+	1222: database.ErrLockTimeout,
+}
+
+var (
+	// Violation of UNIQUE KEY constraint 'UQ_users'. Cannot insert duplicate key in object 'dbo.users'.
+	duplicatedKeyMatcher = regexp.MustCompile(`constraint '([^']+)'\. Cannot insert duplicate key in object '([^']+)'`)
+	// Cannot insert duplicate key row in object 'dbo.users' with unique index 'idx_users_name'.
+	duplicatedIndexMatcher = regexp.MustCompile(`in object '([^']+)' with unique index '([^']+)'`)
+	// The INSERT statement conflicted with the CHECK constraint "chk_users_age". The conflict occurred in database "db", table "dbo.users"
+	conflictMatcher = regexp.MustCompile(`conflicted with the ([\w ]+?) constraint "([^"]+)"\. The conflict occurred in database "[^"]+", table "([^"]+)"`)
+	// Cannot insert the value NULL into column 'name', table 'db.dbo.users'
+	notNullMatcher = regexp.MustCompile(`column '[^']+', table '([^']+)'`)
+)
+
+// Translate translate mssql.Error to portable errors
+func (dialector Dialector) Translate(err error) error {
+	var mssqlErr mssql.Error
+	if !errors.As(err, &mssqlErr) {
+		return err
+	}
+
+	translatedErr, ok := errCodes[mssqlErr.Number]
+	if !ok {
+		return err
+	}
+
+	result := &database.TranslatedError{Err: translatedErr, Cause: err}
+	switch translatedErr {
+	case database.ErrDuplicatedKey:
+		if matches := duplicatedKeyMatcher.FindStringSubmatch(mssqlErr.Message); len(matches) == 3 {
+			result.Constraint, result.Table = matches[1], matches[2]
+		} else if matches := duplicatedIndexMatcher.FindStringSubmatch(mssqlErr.Message); len(matches) == 3 {
+			result.Table, result.Constraint = matches[1], matches[2]
+		}
+	case database.ErrForeignKeyViolated:
+		// error 547 is reported for both foreign key and check constraints
+		if matches := conflictMatcher.FindStringSubmatch(mssqlErr.Message); len(matches) == 4 {
+			if matches[1] == "CHECK" {
+				result.Err = database.ErrCheckConstraintViolated
+			}
+			result.Constraint, result.Table = matches[2], matches[3]
+		}
+	case database.ErrNotNullViolated:
+		if matches := notNullMatcher.FindStringSubmatch(mssqlErr.Message); len(matches) == 2 {
+			result.Table = matches[1]
+		}
+	}
+	return result
+}
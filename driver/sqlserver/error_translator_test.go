@@ -0,0 +1,74 @@
+package sqlserver_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/sqlserver"
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+func TestTranslate(t *testing.T) {
+	results := []struct {
+		Name       string
+		Err        mssql.Error
+		Translated error
+		Table      string
+		Constraint string
+	}{
+		{
+			"DuplicatedKey",
+			mssql.Error{Number: 2627, Message: "Violation of UNIQUE KEY constraint 'UQ_users_name'. Cannot insert duplicate key in object 'dbo.users'. The duplicate key value is (jinzhu)."},
+			database.ErrDuplicatedKey, "dbo.users", "UQ_users_name",
+		},
+		{
+			"DuplicatedIndex",
+			mssql.Error{Number: 2601, Message: "Cannot insert duplicate key row in object 'dbo.users' with unique index 'idx_users_name'. The duplicate key value is (jinzhu)."},
+			database.ErrDuplicatedKey, "dbo.users", "idx_users_name",
+		},
+		{
+			"ForeignKeyViolated",
+			mssql.Error{Number: 547, Message: `The INSERT statement conflicted with the FOREIGN KEY constraint "fk_users_orders". The conflict occurred in database "db", table "dbo.users", column 'id'.`},
+			database.ErrForeignKeyViolated, "dbo.users", "fk_users_orders",
+		},
+		{
+			"CheckConstraintViolated",
+			mssql.Error{Number: 547, Message: `The INSERT statement conflicted with the CHECK constraint "chk_users_age". The conflict occurred in database "db", table "dbo.users", column 'age'.`},
+			database.ErrCheckConstraintViolated, "dbo.users", "chk_users_age",
+		},
+		{
+			"NotNullViolated",
+			mssql.Error{Number: 515, Message: "Cannot insert the value NULL into column 'name', table 'db.dbo.users'; column does not allow nulls. INSERT fails."},
+			database.ErrNotNullViolated, "db.dbo.users", "",
+		},
+		{"Deadlock", mssql.Error{Number: 1205, Message: "Transaction was deadlocked"}, database.ErrDeadlock, "", ""},
+		{"LockTimeout", mssql.Error{Number: 1222, Message: "Lock request time out period exceeded."}, database.ErrLockTimeout, "", ""},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			err := sqlserver.Dialector{}.Translate(result.Err)
+			if !errors.Is(err, result.Translated) {
+				t.Fatalf("expects %v, got %v", result.Translated, err)
+			}
+
+			var translated *database.TranslatedError
+			if !errors.As(err, &translated) || translated.Table != result.Table || translated.Constraint != result.Constraint {
+				t.Errorf("expects table %v constraint %v, got %+v", result.Table, result.Constraint, translated)
+			}
+
+			var cause mssql.Error
+			if !errors.As(err, &cause) || cause.Number != result.Err.Number {
+				t.Errorf("driver error should be reachable, got %v", cause)
+			}
+		})
+	}
+
+	for _, err := range []error{errors.New("unknown"), mssql.Error{Number: 208}} {
+		var translated *database.TranslatedError
+		if errors.As(sqlserver.Dialector{}.Translate(err), &translated) {
+			t.Errorf("error %v shouldn't be translated, got %v", err, translated)
+		}
+	}
+}
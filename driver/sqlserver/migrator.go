@@ -0,0 +1,479 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/migrator"
+	"github.com/driver005/database/schema"
+)
+
+const columnTypesSql = `
+SELECT
+	c.name,
+	t.name,
+	c.max_length,
+	c.precision,
+	c.scale,
+	c.is_nullable,
+	c.is_identity,
+	dc.definition,
+	CAST(ep.value AS nvarchar(MAX)),
+	(SELECT COUNT(*) FROM sys.index_columns ic JOIN sys.indexes i ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+		WHERE ic.object_id = c.object_id AND ic.column_id = c.column_id AND i.is_primary_key = 1),
+	(SELECT COUNT(*) FROM sys.index_columns ic JOIN sys.indexes i ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+		WHERE ic.object_id = c.object_id AND ic.column_id = c.column_id AND i.is_unique_constraint = 1
+		AND (SELECT COUNT(*) FROM sys.index_columns ic2 WHERE ic2.object_id = i.object_id AND ic2.index_id = i.index_id) = 1)
+FROM
+	sys.columns c
+	JOIN sys.types t ON t.user_type_id = c.user_type_id
+	LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+	LEFT JOIN sys.extended_properties ep ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+WHERE
+	c.object_id = OBJECT_ID(?)
+ORDER BY
+	c.column_id`
+
+const indexSql = `
+SELECT
+	i.name AS index_name,
+	c.name AS column_name,
+	i.is_unique AS is_unique,
+	i.is_primary_key AS is_primary_key
+FROM
+	sys.indexes i
+	JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+	JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE
+	i.object_id = OBJECT_ID(?)
+	AND i.name IS NOT NULL
+	AND ic.is_included_column = 0
+ORDER BY
+	i.name,
+	ic.key_ordinal`
+
+var typeAliasMap = map[string][]string{
+	"decimal": {"numeric"},
+	"numeric": {"decimal"},
+}
+
+type Migrator struct {
+	migrator.Migrator
+}
+
+// objectName returns table's name for OBJECT_ID, schema qualified tables are quoted with their schema
+func (m Migrator) objectName(stmt *database.Statement, table string) string {
+	if stmt.TableExpr != nil && table == stmt.Table && len(stmt.TableExpr.Vars) == 0 {
+		return stmt.TableExpr.SQL
+	}
+	return table
+}
+
+func (m Migrator) CurrentDatabase() (name string) {
+	m.DB.Raw("SELECT DB_NAME()").Row().Scan(&name)
+	return
+}
+
+func (m Migrator) GetTables() (tableList []string, err error) {
+	return tableList, m.DB.Raw("SELECT name FROM sys.tables WHERE schema_id = SCHEMA_ID()").Scan(&tableList).Error
+}
+
+func (m Migrator) HasTable(value interface{}) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		return m.DB.Raw(
+			"SELECT count(*) FROM sys.tables WHERE object_id = OBJECT_ID(?)", m.objectName(stmt, stmt.Table),
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+// DropTable drop tables, foreign keys referencing the tables are dropped first
+func (m Migrator) DropTable(values ...interface{}) error {
+	values = m.ReorderModels(values, false)
+	tx := m.DB.Session(&database.Session{})
+	for i := len(values) - 1; i >= 0; i-- {
+		if err := m.RunWithValue(values[i], func(stmt *database.Statement) error {
+			type constraint struct {
+				Name   string
+				Parent string
+			}
+
+			var constraints []constraint
+			if err := tx.Raw(
+				"SELECT name, OBJECT_SCHEMA_NAME(parent_object_id) + '.' + OBJECT_NAME(parent_object_id) AS parent FROM sys.foreign_keys WHERE referenced_object_id = OBJECT_ID(?)",
+				m.objectName(stmt, stmt.Table),
+			).Scan(&constraints).Error; err != nil {
+				return err
+			}
+
+			for _, c := range constraints {
+				if err := tx.Exec("ALTER TABLE ? DROP CONSTRAINT ?", clause.Table{Name: c.Parent}, clause.Column{Name: c.Name}).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Exec("DROP TABLE IF EXISTS ?", m.CurrentTable(stmt)).Error
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m Migrator) RenameTable(oldName, newName interface{}) error {
+	var oldTable, newTable string
+	if v, ok := oldName.(string); ok {
+		oldTable = v
+	} else if err := m.RunWithValue(oldName, func(stmt *database.Statement) error {
+		oldTable = m.objectName(stmt, stmt.Table)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if v, ok := newName.(string); ok {
+		newTable = v
+	} else if err := m.RunWithValue(newName, func(stmt *database.Statement) error {
+		newTable = stmt.Table
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return m.DB.Exec("EXEC sp_rename ?, ?", oldTable, newTable).Error
+}
+
+func (m Migrator) HasColumn(value interface{}, field string) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		name := field
+		if stmt.Schema != nil {
+			if field := stmt.Schema.LookUpField(field); field != nil {
+				name = field.DBName
+			}
+		}
+
+		return m.DB.Raw(
+			"SELECT count(*) FROM sys.columns WHERE object_id = OBJECT_ID(?) AND name = ?",
+			m.objectName(stmt, stmt.Table), name,
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+// AlterColumn ALTER COLUMN only changes the type and nullability, IDENTITY can't be added to existing columns
+func (m Migrator) AlterColumn(value interface{}, field string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		if field := stmt.Schema.LookUpField(field); field != nil {
+			dataType := strings.TrimSuffix(m.DataTypeOf(field), " IDENTITY(1,1)")
+			if field.NotNull {
+				dataType += " NOT NULL"
+			} else {
+				dataType += " NULL"
+			}
+
+			return m.DB.Exec(
+				"ALTER TABLE ? ALTER COLUMN ? ?",
+				m.CurrentTable(stmt), clause.Column{Name: field.DBName}, clause.Expr{SQL: dataType},
+			).Error
+		}
+		return fmt.Errorf("failed to look up field with name: %s", field)
+	})
+}
+
+func (m Migrator) RenameColumn(value interface{}, oldName, newName string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		if stmt.Schema != nil {
+			if field := stmt.Schema.LookUpField(oldName); field != nil {
+				oldName = field.DBName
+			}
+
+			if field := stmt.Schema.LookUpField(newName); field != nil {
+				newName = field.DBName
+			}
+		}
+
+		return m.DB.Exec(
+			"EXEC sp_rename ?, ?, 'COLUMN'", m.objectName(stmt, stmt.Table)+"."+oldName, newName,
+		).Error
+	})
+}
+
+// DropColumn drop column, its default constraint is dropped first
+func (m Migrator) DropColumn(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		if stmt.Schema != nil {
+			if field := stmt.Schema.LookUpField(name); field != nil {
+				name = field.DBName
+			}
+		}
+
+		var constraints []string
+		if err := m.DB.Raw(
+			"SELECT dc.name FROM sys.default_constraints dc JOIN sys.columns c ON c.object_id = dc.parent_object_id AND c.column_id = dc.parent_column_id WHERE dc.parent_object_id = OBJECT_ID(?) AND c.name = ?",
+			m.objectName(stmt, stmt.Table), name,
+		).Scan(&constraints).Error; err != nil {
+			return err
+		}
+
+		for _, constraint := range constraints {
+			if err := m.DB.Exec("ALTER TABLE ? DROP CONSTRAINT ?", m.CurrentTable(stmt), clause.Column{Name: constraint}).Error; err != nil {
+				return err
+			}
+		}
+
+		return m.DB.Exec("ALTER TABLE ? DROP COLUMN ?", m.CurrentTable(stmt), clause.Column{Name: name}).Error
+	})
+}
+
+func (m Migrator) ColumnTypes(value interface{}) ([]database.ColumnType, error) {
+	columnTypes := make([]database.ColumnType, 0)
+	execErr := m.RunWithValue(value, func(stmt *database.Statement) error {
+		rows, err := m.DB.Raw(columnTypesSql, m.objectName(stmt, stmt.Table)).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				column = &migrator.ColumnType{
+					PrimaryKeyValue: sql.NullBool{Valid: true},
+					UniqueValue:     sql.NullBool{Valid: true},
+				}
+				maxLength, precision, scale sql.NullInt64
+				primary, unique             int64
+			)
+
+			if err := rows.Scan(
+				&column.NameValue, &column.DataTypeValue, &maxLength, &precision, &scale, &column.NullableValue,
+				&column.AutoIncrementValue, &column.DefaultValueValue, &column.CommentValue, &primary, &unique,
+			); err != nil {
+				return err
+			}
+
+			column.PrimaryKeyValue.Bool, column.UniqueValue.Bool = primary > 0, unique > 0
+			column.ColumnTypeValue = column.DataTypeValue
+
+			switch dataType := column.DataTypeValue.String; dataType {
+			case "nvarchar", "nchar", "varchar", "char", "varbinary", "binary":
+				if maxLength.Int64 == -1 {
+					column.ColumnTypeValue.String = dataType + "(MAX)"
+				} else {
+					// max_length is in bytes, two bytes per character of unicode types
+					if strings.HasPrefix(dataType, "n") {
+						maxLength.Int64 /= 2
+					}
+					column.LengthValue = maxLength
+					column.ColumnTypeValue.String = fmt.Sprintf("%s(%d)", dataType, maxLength.Int64)
+				}
+			case "decimal", "numeric":
+				column.DecimalSizeValue, column.ScaleValue = precision, scale
+				column.ColumnTypeValue.String = fmt.Sprintf("%s(%d,%d)", dataType, precision.Int64, scale.Int64)
+			}
+
+			if column.DefaultValueValue.Valid {
+				column.DefaultValueValue.String = parseDefaultValue(column.DefaultValueValue.String)
+			}
+
+			columnTypes = append(columnTypes, column)
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// assign sql column type
+		rawRows, err := m.DB.Session(&database.Session{}).Table(stmt.Table).Where("1 = 0").Rows()
+		if err != nil {
+			return err
+		}
+		defer rawRows.Close()
+
+		rawColumnTypes, err := rawRows.ColumnTypes()
+		if err != nil {
+			return err
+		}
+
+		for _, columnType := range columnTypes {
+			for _, c := range rawColumnTypes {
+				if c.Name() == columnType.Name() {
+					columnType.(*migrator.ColumnType).SQLColumnType = c
+					break
+				}
+			}
+		}
+		return nil
+	})
+
+	return columnTypes, execErr
+}
+
+// parseDefaultValue strips parentheses and unicode prefix of default constraint definitions, e.g. ((18)), (N'active')
+func parseDefaultValue(definition string) string {
+	for len(definition) > 1 && definition[0] == '(' && definition[len(definition)-1] == ')' {
+		definition = definition[1 : len(definition)-1]
+	}
+
+	if strings.HasPrefix(definition, "N'") {
+		definition = definition[1:]
+	}
+
+	if len(definition) > 1 && definition[0] == '\'' && definition[len(definition)-1] == '\'' {
+		definition = strings.ReplaceAll(definition[1:len(definition)-1], "''", "'")
+	}
+	return definition
+}
+
+func (m Migrator) HasConstraint(value interface{}, name string) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
+		if constraint != nil {
+			name = constraint.Name
+		} else if chk != nil {
+			name = chk.Name
+		}
+
+		return m.DB.Raw(
+			"SELECT count(*) FROM sys.objects WHERE type IN ('C', 'F', 'PK', 'UQ') AND parent_object_id = OBJECT_ID(?) AND name = ?",
+			m.objectName(stmt, table), name,
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+func (m Migrator) BuildIndexOptions(opts []schema.IndexOption, stmt *database.Statement) (results []interface{}) {
+	for _, opt := range opts {
+		str := stmt.Quote(opt.DBName)
+		if opt.Expression != "" {
+			str = opt.Expression
+		}
+
+		if opt.Sort != "" {
+			str += " " + opt.Sort
+		}
+		results = append(results, clause.Expr{SQL: str})
+	}
+	return
+}
+
+// CreateIndex create index, index's WHERE creates a filtered index
+func (m Migrator) CreateIndex(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			opts := m.BuildIndexOptions(idx.Fields, stmt)
+			values := []interface{}{clause.Column{Name: idx.Name}, m.CurrentTable(stmt), opts}
+
+			createIndexSQL := "CREATE "
+			if idx.Class != "" {
+				createIndexSQL += idx.Class + " "
+			}
+			createIndexSQL += "INDEX ? ON ??"
+
+			if idx.Where != "" {
+				createIndexSQL += " WHERE " + idx.Where
+			}
+
+			if idx.Option != "" {
+				createIndexSQL += " " + idx.Option
+			}
+
+			return m.DB.Exec(createIndexSQL, values...).Error
+		}
+
+		return fmt.Errorf("failed to create index with name %s", name)
+	})
+}
+
+func (m Migrator) HasIndex(value interface{}, name string) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *database.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+
+		return m.DB.Raw(
+			"SELECT count(*) FROM sys.indexes WHERE object_id = OBJECT_ID(?) AND name = ?",
+			m.objectName(stmt, stmt.Table), name,
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+func (m Migrator) RenameIndex(value interface{}, oldName, newName string) error {
+	return m.RunWithValue(value, func(stmt *database.Statement) error {
+		return m.DB.Exec(
+			"EXEC sp_rename ?, ?, 'INDEX'", m.objectName(stmt, stmt.Table)+"."+oldName, newName,
+		).Error
+	})
+}
+
+func (m Migrator) GetIndexes(value interface{}) ([]database.Index, error) {
+	indexes := make([]database.Index, 0)
+	err := m.RunWithValue(value, func(stmt *database.Statement) error {
+		result := make([]*Index, 0)
+		if err := m.DB.Raw(indexSql, m.objectName(stmt, stmt.Table)).Scan(&result).Error; err != nil {
+			return err
+		}
+
+		indexMap := map[string]*migrator.Index{}
+		for _, idx := range result {
+			index, ok := indexMap[idx.IndexName]
+			if !ok {
+				index = &migrator.Index{
+					TableName:       stmt.Table,
+					NameValue:       idx.IndexName,
+					PrimaryKeyValue: sql.NullBool{Bool: idx.IsPrimaryKey, Valid: true},
+					UniqueValue:     sql.NullBool{Bool: idx.IsUnique, Valid: true},
+				}
+				indexMap[idx.IndexName] = index
+				indexes = append(indexes, index)
+			}
+			index.ColumnList = append(index.ColumnList, idx.ColumnName)
+		}
+		return nil
+	})
+	return indexes, err
+}
+
+// Index table index info
+type Index struct {
+	IndexName    string `database:"column:index_name"`
+	ColumnName   string `database:"column:column_name"`
+	IsUnique     bool   `database:"column:is_unique"`
+	IsPrimaryKey bool   `database:"column:is_primary_key"`
+}
+
+func (m Migrator) HasView(name string) bool {
+	var count int64
+	m.DB.Raw("SELECT count(*) FROM sys.views WHERE object_id = OBJECT_ID(?)", name).Row().Scan(&count)
+	return count > 0
+}
+
+// CreateView create view, Replace uses CREATE OR ALTER VIEW
+func (m Migrator) CreateView(name string, option database.ViewOption) error {
+	query, err := m.BuildViewQuery(option)
+	if err != nil {
+		return err
+	}
+
+	createViewSQL := "CREATE "
+	if option.Replace {
+		createViewSQL += "OR ALTER "
+	}
+	createViewSQL += "VIEW ? AS ?"
+
+	if option.CheckOption != "" {
+		createViewSQL += " " + option.CheckOption
+	}
+
+	return m.DB.Exec(createViewSQL, clause.Table{Name: name}, clause.Expr{SQL: query}).Error
+}
+
+func (m Migrator) GetTypeAliases(databaseTypeName string) []string {
+	return typeAliasMap[databaseTypeName]
+}
@@ -0,0 +1,351 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/callbacks"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/migrator"
+	"github.com/driver005/database/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// DriverName the default driver name for SQL Server
+const DriverName = "sqlserver"
+
+type Config struct {
+	DriverName string
+	DSN        string
+	// DefaultStringSize size of indexed or primary string columns without size, defaults to 256
+	DefaultStringSize int
+	Conn              database.ConnPool
+}
+
+type Dialector struct {
+	*Config
+}
+
+func Open(dsn string) database.Dialector {
+	return &Dialector{&Config{DSN: dsn}}
+}
+
+func New(config Config) database.Dialector {
+	return &Dialector{Config: &config}
+}
+
+func (dialector Dialector) Name() string {
+	return "sqlserver"
+}
+
+func (dialector Dialector) Initialize(db *database.DB) (err error) {
+	if dialector.DriverName == "" {
+		dialector.DriverName = DriverName
+	}
+
+	if dialector.DefaultStringSize == 0 {
+		dialector.DefaultStringSize = 256
+	}
+
+	// OUTPUT is rendered by the RETURNING clause builder, it goes before WHERE in UPDATE and DELETE statements,
+	// locking is rendered by the FROM clause builder as table hints
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{
+		CreateClauses: []string{"WITH", "INSERT", "VALUES", "ON CONFLICT"},
+		QueryClauses:  []string{"WITH", "SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT"},
+		UpdateClauses: []string{"WITH", "UPDATE", "SET", "RETURNING", "WHERE"},
+		DeleteClauses: []string{"WITH", "DELETE", "FROM", "RETURNING", "WHERE"},
+	})
+	if err = db.Callback().Create().Replace("database:create", Create); err != nil {
+		return err
+	}
+
+	if dialector.Conn != nil {
+		db.ConnPool = dialector.Conn
+	} else {
+		db.ConnPool, err = sql.Open(dialector.DriverName, dialector.DSN)
+		if err != nil {
+			return err
+		}
+	}
+
+	for k, v := range dialector.ClauseBuilders() {
+		db.ClauseBuilders[k] = v
+	}
+	return
+}
+
+const (
+	// ClauseLimit for clause.ClauseBuilder LIMIT key
+	ClauseLimit = "LIMIT"
+	// ClauseFrom for clause.ClauseBuilder FROM key
+	ClauseFrom = "FROM"
+	// ClauseReturning for clause.ClauseBuilder RETURNING key
+	ClauseReturning = "RETURNING"
+)
+
+func (dialector Dialector) ClauseBuilders() map[string]clause.ClauseBuilder {
+	return map[string]clause.ClauseBuilder{
+		ClauseLimit: func(c clause.Clause, builder clause.Builder) {
+			limit, ok := c.Expression.(clause.Limit)
+			if !ok {
+				c.Build(builder)
+				return
+			}
+
+			hasLimit := limit.Limit != nil && *limit.Limit >= 0
+			if !hasLimit && limit.Offset <= 0 {
+				return
+			}
+
+			// OFFSET ... FETCH requires ORDER BY
+			if stmt, ok := builder.(*database.Statement); ok {
+				if _, ok := stmt.Clauses["ORDER BY"]; !ok {
+					if stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil {
+						builder.WriteString("ORDER BY ")
+						builder.WriteQuoted(clause.Column{Table: clause.CurrentTable, Name: stmt.Schema.PrioritizedPrimaryField.DBName})
+						builder.WriteByte(' ')
+					} else {
+						builder.WriteString("ORDER BY (SELECT NULL) ")
+					}
+				}
+			}
+
+			builder.WriteString("OFFSET ")
+			builder.WriteString(strconv.Itoa(limit.Offset))
+			builder.WriteString(" ROWS")
+
+			if hasLimit {
+				builder.WriteString(" FETCH NEXT ")
+				builder.WriteString(strconv.Itoa(*limit.Limit))
+				builder.WriteString(" ROWS ONLY")
+			}
+		},
+		ClauseFrom: func(c clause.Clause, builder clause.Builder) {
+			// SQL Server locks with table hints, e.g. WITH (UPDLOCK, ROWLOCK), instead of FOR UPDATE
+			from, ok := c.Expression.(clause.From)
+			stmt, isStmt := builder.(*database.Statement)
+			if !ok || !isStmt {
+				c.Build(builder)
+				return
+			}
+
+			locking, ok := stmt.Clauses["FOR"].Expression.(clause.Locking)
+			if !ok {
+				c.Build(builder)
+				return
+			}
+
+			hints, err := tableHints(locking)
+			if err != nil {
+				stmt.AddError(err)
+				return
+			}
+
+			if len(from.Tables) == 0 {
+				from.Tables = []clause.Table{{Name: clause.CurrentTable}}
+			}
+
+			var locked bool
+			builder.WriteString("FROM ")
+			for idx, table := range from.Tables {
+				if idx > 0 {
+					builder.WriteByte(',')
+				}
+
+				builder.WriteQuoted(table)
+
+				name := table.Name
+				if name == clause.CurrentTable {
+					name = stmt.Table
+				}
+
+				if locking.Table.Name == "" || locking.Table.Name == table.Name || locking.Table.Name == name || locking.Table.Name == table.Alias {
+					builder.WriteString(hints)
+					locked = true
+				}
+			}
+
+			// hints of joined tables go to the joins, which are usually raw SQL
+			if !locked {
+				stmt.AddError(fmt.Errorf("%w: locking of table %s isn't in FROM", database.ErrUnsupportedDriver, locking.Table.Name))
+			}
+
+			for _, join := range from.Joins {
+				builder.WriteByte(' ')
+				join.Build(builder)
+			}
+		},
+		ClauseReturning: func(c clause.Clause, builder clause.Builder) {
+			returning, ok := c.Expression.(clause.Returning)
+			if !ok {
+				c.Build(builder)
+				return
+			}
+
+			prefix := "INSERTED"
+			if stmt, ok := builder.(*database.Statement); ok {
+				if _, ok := stmt.Clauses["DELETE"]; ok {
+					prefix = "DELETED"
+				}
+			}
+			writeOutput(builder, prefix, returning.Columns)
+		},
+	}
+}
+
+// tableHints returns the table hints of locking, UPDATE locks rows with update locks, SHARE holds shared locks of
+// rows until the transaction ends, NOWAIT and SKIP LOCKED options are the NOWAIT and READPAST hints
+func tableHints(locking clause.Locking) (string, error) {
+	var hints []string
+	switch strings.ToUpper(locking.Strength) {
+	case "UPDATE", "NO KEY UPDATE":
+		hints = append(hints, "UPDLOCK", "ROWLOCK")
+	case "SHARE", "KEY SHARE":
+		hints = append(hints, "HOLDLOCK", "ROWLOCK")
+	default:
+		return "", fmt.Errorf("%w: locking strength %s isn't supported by sqlserver", database.ErrUnsupportedDriver, locking.Strength)
+	}
+
+	switch strings.ToUpper(locking.Options) {
+	case "":
+	case "NOWAIT":
+		hints = append(hints, "NOWAIT")
+	case "SKIP LOCKED":
+		hints = append(hints, "READPAST")
+	default:
+		return "", fmt.Errorf("%w: locking options %s aren't supported by sqlserver", database.ErrUnsupportedDriver, locking.Options)
+	}
+	return " WITH (" + strings.Join(hints, ", ") + ")", nil
+}
+
+// writeOutput writes OUTPUT clause of prefix, INSERTED or DELETED, all columns are returned if columns is empty
+func writeOutput(builder clause.Builder, prefix string, columns []clause.Column) {
+	builder.WriteString("OUTPUT ")
+	if len(columns) == 0 || (len(columns) == 1 && columns[0].Name == "*") {
+		builder.WriteString(prefix)
+		builder.WriteString(".*")
+		return
+	}
+
+	for idx, column := range columns {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(prefix)
+		builder.WriteByte('.')
+		builder.WriteQuoted(column.Name)
+	}
+}
+
+func (dialector Dialector) DefaultValueOf(field *schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+func (dialector Dialector) Migrator(db *database.DB) database.Migrator {
+	return Migrator{migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   dialector,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+func (dialector Dialector) BindVarTo(writer clause.Writer, stmt *database.Statement, v interface{}) {
+	writer.WriteString("@p")
+	writer.WriteString(strconv.Itoa(len(stmt.Vars)))
+}
+
+func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
+	for idx, part := range strings.Split(str, ".") {
+		if idx > 0 {
+			writer.WriteByte('.')
+		}
+
+		// already quoted, e.g. [dbo].[users]
+		if len(part) > 1 && part[0] == '[' && part[len(part)-1] == ']' {
+			writer.WriteString(part)
+			continue
+		}
+
+		writer.WriteByte('[')
+		writer.WriteString(strings.ReplaceAll(part, "]", "]]"))
+		writer.WriteByte(']')
+	}
+}
+
+var numericPlaceholder = regexp.MustCompile(`@p(\d+)`)
+
+func (dialector Dialector) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, numericPlaceholder, `'`, vars...)
+}
+
+func (dialector Dialector) DataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "bit"
+	case schema.Int, schema.Uint:
+		size := field.Size
+		if field.DataType == schema.Uint {
+			size++
+		}
+
+		var sqlType string
+		switch {
+		case size <= 16:
+			sqlType = "smallint"
+		case size <= 32:
+			sqlType = "int"
+		default:
+			sqlType = "bigint"
+		}
+
+		if field.AutoIncrement {
+			return sqlType + " IDENTITY(1,1)"
+		}
+		return sqlType
+	case schema.Float:
+		if field.Precision > 0 {
+			if field.Scale > 0 {
+				return fmt.Sprintf("decimal(%d, %d)", field.Precision, field.Scale)
+			}
+			return fmt.Sprintf("decimal(%d)", field.Precision)
+		}
+		return "float"
+	case schema.String:
+		size := field.Size
+		_, hasIndex := field.TagSettings["INDEX"]
+		_, hasUniqueIndex := field.TagSettings["UNIQUEINDEX"]
+		if size == 0 && (field.PrimaryKey || field.Unique || hasIndex || hasUniqueIndex) {
+			// nvarchar(MAX) can't be indexed
+			size = dialector.DefaultStringSize
+		}
+
+		if size > 0 && size <= 4000 {
+			return fmt.Sprintf("nvarchar(%d)", size)
+		}
+		return "nvarchar(MAX)"
+	case schema.Time:
+		if field.Precision > 0 {
+			return fmt.Sprintf("datetimeoffset(%d)", field.Precision)
+		}
+		return "datetimeoffset"
+	case schema.Bytes:
+		return "varbinary(MAX)"
+	case schema.JSON:
+		return "nvarchar(MAX)"
+	}
+
+	return string(field.DataType)
+}
+
+func (dialector Dialector) SavePoint(tx *database.DB, name string) error {
+	return tx.Exec("SAVE TRANSACTION " + name).Error
+}
+
+func (dialector Dialector) RollbackTo(tx *database.DB, name string) error {
+	return tx.Exec("ROLLBACK TRANSACTION " + name).Error
+}
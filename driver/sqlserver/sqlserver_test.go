@@ -0,0 +1,166 @@
+package sqlserver_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/driver/sqlserver"
+	"github.com/driver005/database/logger"
+)
+
+type User struct {
+	ID   uint
+	Name string `database:"size:64"`
+	Age  int    `database:"default:18"`
+}
+
+var db, _ = database.Open(sqlserver.Open("sqlserver://localhost"), &database.Config{
+	DryRun:                 true,
+	DisableAutomaticPing:   true,
+	SkipDefaultTransaction: true,
+})
+
+func TestDryRun(t *testing.T) {
+	results := []struct {
+		Name  string
+		Query func(tx *database.DB) *database.DB
+		SQL   string
+		Vars  []interface{}
+	}{
+		{
+			"Create",
+			func(tx *database.DB) *database.DB { return tx.Create(&User{Name: "jinzhu"}) },
+			"INSERT INTO [users] ([name],[age]) OUTPUT INSERTED.[id] VALUES (@p1,@p2);",
+			[]interface{}{"jinzhu", int64(18)},
+		},
+		{
+			"CreateWithIdentity",
+			func(tx *database.DB) *database.DB { return tx.Create(&User{ID: 3, Name: "jinzhu", Age: 20}) },
+			"SET IDENTITY_INSERT [users] ON;INSERT INTO [users] ([name],[age],[id]) OUTPUT INSERTED.[id] VALUES (@p1,@p2,@p3);SET IDENTITY_INSERT [users] OFF;",
+			[]interface{}{"jinzhu", 20, uint(3)},
+		},
+		{
+			"CreateReturning",
+			func(tx *database.DB) *database.DB {
+				return tx.Clauses(clause.Returning{}).Create(&User{Name: "jinzhu", Age: 20})
+			},
+			"INSERT INTO [users] ([name],[age]) OUTPUT INSERTED.* VALUES (@p1,@p2);",
+			[]interface{}{"jinzhu", 20},
+		},
+		{
+			"Upsert",
+			func(tx *database.DB) *database.DB {
+				return tx.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "name"}},
+					DoUpdates: clause.AssignmentColumns([]string{"age"}),
+				}).Create(&User{Name: "jinzhu", Age: 20})
+			},
+			"MERGE INTO [users] WITH (HOLDLOCK) USING (VALUES (@p1,@p2)) AS excluded ([name],[age]) ON [users].[name] = [excluded].[name] " +
+				"WHEN MATCHED THEN UPDATE SET [age]=[excluded].[age] " +
+				"WHEN NOT MATCHED THEN INSERT ([name],[age]) VALUES ([excluded].[name],[excluded].[age]) OUTPUT INSERTED.[id];",
+			[]interface{}{"jinzhu", 20},
+		},
+		{
+			"UpsertDoNothing",
+			func(tx *database.DB) *database.DB {
+				return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&User{ID: 1, Name: "jinzhu", Age: 20})
+			},
+			"SET IDENTITY_INSERT [users] ON;MERGE INTO [users] WITH (HOLDLOCK) USING (VALUES (@p1,@p2,@p3)) AS excluded ([name],[age],[id]) ON [users].[id] = [excluded].[id] " +
+				"WHEN NOT MATCHED THEN INSERT ([name],[age],[id]) VALUES ([excluded].[name],[excluded].[age],[excluded].[id]) OUTPUT INSERTED.[id];SET IDENTITY_INSERT [users] OFF;",
+			[]interface{}{"jinzhu", 20, uint(1)},
+		},
+		{
+			"LimitOffset",
+			func(tx *database.DB) *database.DB { return tx.Limit(10).Offset(20).Find(&[]User{}) },
+			"SELECT * FROM [users] ORDER BY [users].[id] OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+			nil,
+		},
+		{
+			"LimitOrder",
+			func(tx *database.DB) *database.DB {
+				return tx.Where("name = ?", "jinzhu").Order("age desc").Limit(1).Find(&[]User{})
+			},
+			"SELECT * FROM [users] WHERE name = @p1 ORDER BY age desc OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY",
+			[]interface{}{"jinzhu"},
+		},
+		{
+			"UpdateReturning",
+			func(tx *database.DB) *database.DB {
+				return tx.Model(&User{ID: 1}).Clauses(clause.Returning{Columns: []clause.Column{{Name: "age"}}}).Update("age", 20)
+			},
+			"UPDATE [users] SET [age]=@p1 OUTPUT INSERTED.[age] WHERE [id] = @p2",
+			[]interface{}{20, uint(1)},
+		},
+		{
+			"DeleteReturning",
+			func(tx *database.DB) *database.DB {
+				return tx.Clauses(clause.Returning{}).Delete(&User{ID: 1})
+			},
+			"DELETE FROM [users] OUTPUT DELETED.* WHERE [users].[id] = @p1",
+			[]interface{}{uint(1)},
+		},
+		{
+			"LockingUpdate",
+			func(tx *database.DB) *database.DB {
+				return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("age > ?", 18).Limit(1).Find(&[]User{})
+			},
+			"SELECT * FROM [users] WITH (UPDLOCK, ROWLOCK) WHERE age > @p1 ORDER BY [users].[id] OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY",
+			[]interface{}{18},
+		},
+		{
+			"LockingShareSkipLocked",
+			func(tx *database.DB) *database.DB {
+				return tx.Clauses(clause.Locking{Strength: "SHARE", Options: "SKIP LOCKED"}).Find(&[]User{})
+			},
+			"SELECT * FROM [users] WITH (HOLDLOCK, ROWLOCK, READPAST)",
+			nil,
+		},
+		{
+			"LockingOfTable",
+			func(tx *database.DB) *database.DB {
+				return tx.Joins("JOIN companies c ON c.id = users.company_id").
+					Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "users"}, Options: "NOWAIT"}).Find(&[]User{})
+			},
+			"SELECT [users].[id],[users].[name],[users].[age] FROM [users] WITH (UPDLOCK, ROWLOCK, NOWAIT) JOIN companies c ON c.id = users.company_id",
+			nil,
+		},
+		{
+			"Quote",
+			func(tx *database.DB) *database.DB { return tx.Table("dbo.user]s").Find(&[]User{}) },
+			"SELECT * FROM [dbo].[user]]s]",
+			nil,
+		},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			stmt := result.Query(db.Session(&database.Session{})).Statement
+
+			if sql := stmt.SQL.String(); sql != result.SQL {
+				t.Errorf("SQL expects %v got %v", result.SQL, sql)
+			}
+
+			if !reflect.DeepEqual(stmt.Vars, result.Vars) {
+				t.Errorf("Vars expects %+v got %+v", result.Vars, stmt.Vars)
+			}
+		})
+	}
+}
+
+func TestUnsupportedLocking(t *testing.T) {
+	lockings := []clause.Locking{
+		{Strength: "UPDATE", Options: "SKIP LOCKED NOWAIT"},
+		{Strength: "EXCLUSIVE"},
+		{Strength: "UPDATE", Table: clause.Table{Name: "companies"}},
+	}
+
+	for _, locking := range lockings {
+		err := db.Session(&database.Session{Logger: logger.Discard}).Clauses(locking).Find(&[]User{}).Error
+		if !errors.Is(err, database.ErrUnsupportedDriver) {
+			t.Errorf("expects ErrUnsupportedDriver for %+v, got %v", locking, err)
+		}
+	}
+}
@@ -41,4 +41,50 @@ var (
 	ErrInvalidValueOfLength = errors.New("invalid association values, length doesn't match")
 	// ErrPreloadNotAllowed preload is not allowed when count is used
 	ErrPreloadNotAllowed = errors.New("preload is not allowed when count is used")
+	// ErrSubQueryRequired sub query required
+	ErrSubQueryRequired = errors.New("sub query required")
+	// ErrStaleObject the record was updated or deleted since it's loaded, see Version
+	ErrStaleObject = errors.New("stale object, the record has been changed")
+	// ErrDuplicatedKey occurs when there is a unique key constraint violation
+	ErrDuplicatedKey = errors.New("duplicated key not allowed")
+	// ErrForeignKeyViolated occurs when there is a foreign key constraint violation
+	ErrForeignKeyViolated = errors.New("violates foreign key constraint")
+	// ErrCheckConstraintViolated occurs when there is a check constraint violation
+	ErrCheckConstraintViolated = errors.New("violates check constraint")
+	// ErrNotNullViolated occurs when null is written to a not null column
+	ErrNotNullViolated = errors.New("violates not null constraint")
+	// ErrDeadlock occurs when the transaction is chosen as deadlock victim
+	ErrDeadlock = errors.New("deadlock detected")
+	// ErrSerializationFailure occurs when a transaction can't be serialized with concurrent transactions
+	ErrSerializationFailure = errors.New("could not serialize access")
+	// ErrLockTimeout occurs when waiting for a lock timed out
+	ErrLockTimeout = errors.New("lock wait timeout")
 )
+
+// TranslatedError wraps a driver error with the portable error it was translated to, use errors.Is to check the
+// portable error and errors.As to reach the driver error
+//
+//	if errors.Is(err, database.ErrDuplicatedKey) {}
+//
+//	var translated *database.TranslatedError
+//	if errors.As(err, &translated) { translated.Constraint }
+type TranslatedError struct {
+	Err        error // portable error, e.g. ErrDuplicatedKey
+	Cause      error // original driver error
+	Constraint string
+	Table      string
+}
+
+func (e *TranslatedError) Error() string {
+	return e.Cause.Error()
+}
+
+// Is reports whether target is the portable error
+func (e *TranslatedError) Is(target error) bool {
+	return target == e.Err
+}
+
+// Unwrap returns the original driver error
+func (e *TranslatedError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,77 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/logger"
+)
+
+// errorRecorder records errors of traced statements
+type errorRecorder struct {
+	logger.Interface
+	errs []error
+}
+
+func (r *errorRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *errorRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	recorder := &errorRecorder{Interface: logger.Discard}
+	db := openDB(t, &Sale{}).Session(&database.Session{Logger: recorder})
+
+	sales := []Sale{{Region: "east", Amount: 10}, {Region: "west", Amount: 20}}
+	db.Create(&sales)
+	db.Delete(&sales[1])
+
+	results := []struct {
+		Name   string
+		DB     *database.DB
+		Exists bool
+	}{
+		{"Exists", db.Model(&Sale{}).Where("region = ?", "east"), true},
+		{"NotExists", db.Model(&Sale{}).Where("region = ?", "north"), false},
+		{"Deleted", db.Model(&Sale{}).Where("region = ?", "west"), false},
+		{"Unscoped", db.Unscoped().Model(&Sale{}).Where("region = ?", "west"), true},
+		{"Joins", db.Model(&Sale{}).Joins("JOIN sales AS others ON others.id = sales.id AND others.amount > ?", 10), false},
+		{"Ordered", db.Model(&Sale{}).Select("region").Order("amount desc").Limit(0), true},
+		{"Dest", db.Where("amount = ?", 10), true},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			exists := !result.Exists
+			tx := result.DB
+			if result.Name == "Dest" {
+				tx.Statement.Dest = &Sale{}
+			}
+
+			if err := tx.Exists(&exists).Error; err != nil || exists != result.Exists {
+				t.Errorf("exists expects %v, got %v, error %v", result.Exists, exists, err)
+			}
+		})
+	}
+
+	if len(recorder.errs) != 0 {
+		t.Errorf("missing records shouldn't be logged as errors, got %v", recorder.errs)
+	}
+
+	// the select, order and limit clauses are kept for the following finishers
+	tx := db.Unscoped().Model(&Sale{}).Select("region").Order("amount desc").Limit(1)
+	var exists bool
+	tx.Exists(&exists)
+
+	var regions []string
+	if tx.Find(&regions); len(regions) != 1 || regions[0] != "west" {
+		t.Errorf("clauses should be restored after Exists, got %v", regions)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/driver005/database/clause"
 	"github.com/driver005/database/logger"
@@ -243,6 +247,177 @@ func (db *DB) FindInBatches(dest interface{}, batchSize int, fc func(tx *DB, bat
 	return tx
 }
 
+// BatchOptions options of FindInBatchesWithOptions
+type BatchOptions struct {
+	// BatchSize count of records in each batch
+	BatchSize int
+	// Keys ordered unique keys batches are paged by, defaults to the primary keys in ascending order
+	Keys []clause.OrderByColumn
+	// Workers count of goroutines fc is called in, batches are fetched while the workers are busy, at most Workers+1
+	// batches are kept in memory
+	Workers int
+}
+
+// FindInBatchesWithOptions finds all records in batches paged by the keys of opts, filtering on the last key values
+// of the previous batch instead of offsets. With more than one worker, every batch is scanned into a new value of
+// dest's type and has to be read from tx.Statement.Dest in fc. The first error returned by fc stops fetching and
+// skips the pending batches
+//
+//	db.Where("processed = ?", false).FindInBatchesWithOptions(&[]Order{}, database.BatchOptions{
+//		BatchSize: 1000,
+//		Keys:      []clause.OrderByColumn{{Column: clause.Column{Name: "shop_id"}}, {Column: clause.Column{Name: "number"}}},
+//		Workers:   4,
+//	}, func(tx *database.DB, batch int) error {
+//		orders := *tx.Statement.Dest.(*[]Order)
+//		// ...
+//	})
+func (db *DB) FindInBatchesWithOptions(dest interface{}, opts BatchOptions, fc func(tx *DB, batch int) error) *DB {
+	var (
+		tx           = db.Session(&Session{})
+		keys         = opts.Keys
+		fields       []*schema.Field
+		rowsAffected int64
+		batch        int
+	)
+
+	model := tx.Statement.Model
+	if model == nil {
+		model = dest
+	}
+
+	if err := tx.Statement.Parse(model); err != nil {
+		tx.AddError(err)
+		return tx
+	}
+
+	if len(keys) == 0 {
+		for _, field := range tx.Statement.Schema.PrimaryFields {
+			keys = append(keys, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}})
+		}
+	}
+
+	for _, key := range keys {
+		field := tx.Statement.Schema.LookUpField(key.Column.Name)
+		if field == nil {
+			tx.AddError(fmt.Errorf("%w: batch key %s", ErrInvalidField, key.Column.Name))
+			return tx
+		}
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		tx.AddError(ErrPrimaryKeyRequired)
+		return tx
+	}
+
+	if opts.BatchSize <= 0 {
+		tx.AddError(fmt.Errorf("%w: batch size must be greater than zero", ErrInvalidData))
+		return tx
+	}
+
+	type batchTask struct {
+		tx    *DB
+		batch int
+	}
+
+	var (
+		tasks    chan batchTask
+		stop     = make(chan struct{})
+		stopOnce sync.Once
+		fcErr    error
+		wg       sync.WaitGroup
+	)
+
+	abort := func(err error) {
+		stopOnce.Do(func() {
+			fcErr = err
+			close(stop)
+		})
+	}
+
+	if opts.Workers > 1 {
+		tasks = make(chan batchTask)
+		for i := 0; i < opts.Workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for task := range tasks {
+					select {
+					case <-stop:
+					default:
+						if err := fc(task.tx, task.batch); err != nil {
+							abort(err)
+						}
+					}
+				}
+			}()
+		}
+	}
+
+	queryDB := tx.Clauses(clause.OrderBy{Columns: keys}).Session(&Session{})
+	nextDB := queryDB
+	for {
+		batchDest := dest
+		if tasks != nil {
+			batchDest = reflect.New(reflect.TypeOf(dest).Elem()).Interface()
+		}
+
+		result := nextDB.Limit(opts.BatchSize).Find(batchDest)
+		rowsAffected += result.RowsAffected
+		batch++
+
+		if result.Error != nil {
+			tx.AddError(result.Error)
+			break
+		}
+
+		if result.RowsAffected != 0 {
+			fcTx := result.Session(&Session{NewDB: true})
+			fcTx.RowsAffected = result.RowsAffected
+
+			if tasks == nil {
+				if err := fc(fcTx, batch); err != nil {
+					abort(err)
+				}
+			} else {
+				select {
+				case tasks <- batchTask{tx: fcTx, batch: batch}:
+				case <-stop:
+				}
+			}
+		}
+
+		if int(result.RowsAffected) < opts.BatchSize {
+			break
+		}
+
+		select {
+		case <-stop:
+		default:
+			resultsValue := reflect.Indirect(reflect.ValueOf(batchDest))
+			lastValue := resultsValue.Index(resultsValue.Len() - 1)
+			values := make([]interface{}, len(fields))
+			for idx, field := range fields {
+				values[idx], _ = field.ValueOf(tx.Statement.Context, lastValue)
+			}
+			nextDB = queryDB.Clauses(clause.Keyset(keys, values))
+			continue
+		}
+		break
+	}
+
+	if tasks != nil {
+		close(tasks)
+		wg.Wait()
+	}
+
+	if fcErr != nil {
+		tx.AddError(fcErr)
+	}
+	tx.RowsAffected = rowsAffected
+	return tx
+}
+
 func (db *DB) assignInterfacesToValue(values ...interface{}) {
 	for _, value := range values {
 		switch v := value.(type) {
@@ -410,6 +585,188 @@ func (db *DB) Delete(value interface{}, conds ...interface{}) (tx *DB) {
 	return tx.callbacks.Delete().Execute(tx)
 }
 
+// Restore restores soft deleted records matching value and conds. Has one, has many and many2many join rows of
+// associations selected with Select are restored too if they were deleted along with the records, which requires
+// primary keys of value, nested associations aren't restored
+//
+//	db.Select(clause.Associations).Restore(&user)
+func (db *DB) Restore(value interface{}, conds ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if err := tx.Statement.Parse(value); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	sch := tx.Statement.Schema
+	field := softDeleteField(sch)
+	if field == nil {
+		tx.AddError(fmt.Errorf("%w: %s has no soft delete field", ErrInvalidField, sch))
+		return
+	}
+
+	if len(conds) > 0 {
+		if exprs := tx.Statement.BuildCondition(conds[0], conds[1:]...); len(exprs) > 0 {
+			tx.Statement.AddClause(clause.Where{Exprs: exprs})
+		}
+	}
+
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	if _, ok := tx.Statement.Clauses["WHERE"]; !ok && !tx.AllowGlobalUpdate {
+		if _, queryValues := schema.GetIdentityFieldValuesMap(tx.Statement.Context, reflectValue, sch.PrimaryFields); len(queryValues) == 0 {
+			tx.AddError(ErrMissingWhereClause)
+			return
+		}
+	}
+
+	var (
+		relations          []*schema.Relationship
+		selectColumns, _   = tx.Statement.SelectAndOmitColumns(false, false)
+		notDeletedValue    = softDeleteValues(field).notDeletedValue(field)
+		updates            = map[string]interface{}{field.DBName: notDeletedValue}
+		restoreAssociation = func(tx *DB, rel *schema.Relationship, records reflect.Value, deleted clause.Expression) error {
+			tx = tx.Session(&Session{NewDB: true})
+			switch rel.Type {
+			case schema.HasOne, schema.HasMany:
+				queryConds := rel.ToQueryConditions(tx.Statement.Context, records)
+				for _, cond := range queryConds {
+					if c, ok := cond.(clause.IN); ok && len(c.Values) == 0 {
+						return nil
+					}
+				}
+				if deleted != nil {
+					queryConds = append(queryConds, deleted)
+				}
+				return tx.Clauses(clause.Where{Exprs: queryConds}).Restore(reflect.New(rel.FieldSchema.ModelType).Interface()).Error
+			case schema.Many2Many:
+				queryConds := make([]clause.Expression, 0, len(rel.References))
+				foreignFields := make([]*schema.Field, 0, len(rel.References))
+				relForeignKeys := make([]string, 0, len(rel.References))
+				for _, ref := range rel.References {
+					if ref.OwnPrimaryKey {
+						foreignFields = append(foreignFields, ref.PrimaryKey)
+						relForeignKeys = append(relForeignKeys, ref.ForeignKey.DBName)
+					} else if ref.PrimaryValue != "" {
+						queryConds = append(queryConds, clause.Eq{
+							Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName},
+							Value:  ref.PrimaryValue,
+						})
+					}
+				}
+
+				_, foreignValues := schema.GetIdentityFieldValuesMap(tx.Statement.Context, records, foreignFields)
+				if len(foreignValues) == 0 {
+					return nil
+				}
+				column, values := schema.ToQueryValues(rel.JoinTable.Table, relForeignKeys, foreignValues)
+				queryConds = append(queryConds, clause.IN{Column: column, Values: values})
+				if deleted != nil {
+					queryConds = append(queryConds, deleted)
+				}
+				return tx.Table(rel.JoinTable.Table).Clauses(clause.Where{Exprs: queryConds}).Restore(reflect.New(rel.JoinTable.ModelType).Interface()).Error
+			}
+			return nil
+		}
+	)
+
+	for name, selected := range selectColumns {
+		if rel, ok := sch.Relationships.Relations[name]; ok && selected {
+			relSchema := rel.FieldSchema
+			if rel.Type == schema.Many2Many {
+				relSchema = rel.JoinTable
+			}
+
+			if softDeleteField(relSchema) != nil {
+				relations = append(relations, rel)
+			}
+		}
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		return relations[i].Name < relations[j].Name
+	})
+
+	if byField := deletedByField(sch); byField != nil {
+		updates[byField.DBName] = nil
+	}
+
+	tx.Statement.Selects, tx.Statement.Omits = nil, []string{clause.Associations}
+	tx.Statement.Unscoped = true
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: notDeletedValue},
+	}})
+	tx.Statement.Model = value
+	tx.Statement.Dest = updates
+
+	if len(relations) == 0 {
+		return tx.callbacks.Update().Execute(tx)
+	}
+
+	var rowsAffected int64
+	tx.AddError(tx.Transaction(func(tx *DB) error {
+		// associations are restored only if they were deleted along with the record, that is at the same time
+		type deletedAt struct {
+			at        time.Time
+			precision time.Duration
+		}
+
+		var (
+			records  = reflect.New(reflect.SliceOf(sch.ModelType))
+			groups   = map[deletedAt]reflect.Value{}
+			keys     []deletedAt
+			where, _ = tx.Statement.Clauses["WHERE"].Expression.(clause.Where)
+			findTx   = tx.Session(&Session{NewDB: true}).Unscoped().Clauses(where)
+		)
+
+		if _, queryValues := schema.GetIdentityFieldValuesMap(tx.Statement.Context, reflectValue, sch.PrimaryFields); len(queryValues) > 0 {
+			column, values := schema.ToQueryValues(clause.CurrentTable, sch.PrimaryFieldDBNames, queryValues)
+			findTx = findTx.Clauses(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+		}
+
+		if err := findTx.Find(records.Interface()).Error; err != nil {
+			return err
+		}
+
+		for i := 0; i < records.Elem().Len(); i++ {
+			record := records.Elem().Index(i)
+			fieldValue, _ := field.ValueOf(tx.Statement.Context, record)
+
+			var key deletedAt
+			if at, precision, ok := softDeleteValues(field).deletedTime(field, fieldValue); ok {
+				key = deletedAt{at: at, precision: precision}
+			}
+
+			group, ok := groups[key]
+			if !ok {
+				group = reflect.MakeSlice(records.Elem().Type(), 0, 1)
+				keys = append(keys, key)
+			}
+			groups[key] = reflect.Append(group, record)
+		}
+
+		for _, key := range keys {
+			for _, rel := range relations {
+				var deleted clause.Expression
+				if !key.at.IsZero() {
+					relSchema := rel.FieldSchema
+					if rel.Type == schema.Many2Many {
+						relSchema = rel.JoinTable
+					}
+					deleted = deletedWithin(softDeleteField(relSchema), key.at, key.precision)
+				}
+
+				if err := restoreAssociation(tx, rel, groups[key], deleted); err != nil {
+					return err
+				}
+			}
+		}
+
+		result := tx.callbacks.Update().Execute(tx)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	}))
+	tx.RowsAffected = rowsAffected
+	return tx
+}
+
 func (db *DB) Count(count *int64) (tx *DB) {
 	tx = db.getInstance()
 	if tx.Statement.Model == nil {
@@ -472,6 +829,183 @@ func (db *DB) Count(count *int64) (tx *DB) {
 	return
 }
 
+// Exists checks whether any record matches the query, it's wrapped in SELECT EXISTS(...) on postgres and limited to
+// a single row on other dialects, a missing record is never reported as ErrRecordNotFound
+//
+//	var exists bool
+//	db.Model(&User{}).Where("email = ?", email).Exists(&exists)
+func (db *DB) Exists(exists *bool) (tx *DB) {
+	tx = db.getInstance()
+	if tx.Statement.Model == nil {
+		tx.Statement.Model = tx.Statement.Dest
+		defer func() {
+			tx.Statement.Model = nil
+		}()
+	}
+
+	for _, name := range []string{"SELECT", "ORDER BY", "LIMIT"} {
+		if c, ok := tx.Statement.Clauses[name]; ok {
+			defer func(name string, c clause.Clause) {
+				tx.Statement.Clauses[name] = c
+			}(name, c)
+			delete(tx.Statement.Clauses, name)
+		} else {
+			defer delete(tx.Statement.Clauses, name)
+		}
+	}
+
+	if preloads := tx.Statement.Preloads; len(preloads) > 0 {
+		tx.Statement.Preloads = nil
+		defer func() {
+			tx.Statement.Preloads = preloads
+		}()
+	}
+
+	tx.Statement.AddClause(clause.Select{Expression: clause.Expr{SQL: "1"}})
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if tx.Dialector.Name() == "postgres" {
+		// the subquery is built with query callbacks, scopes and soft delete conditions are applied as well
+		rows, err = tx.Session(&Session{NewDB: true}).Raw("SELECT EXISTS(?)", tx).Rows()
+	} else {
+		rows, err = tx.Limit(1).Rows()
+	}
+
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	*exists = false
+	if rows.Next() {
+		if tx.Dialector.Name() == "postgres" {
+			tx.AddError(rows.Scan(exists))
+		} else {
+			*exists = true
+		}
+	}
+	tx.AddError(rows.Err())
+	tx.AddError(rows.Close())
+	return
+}
+
+// Sum sums column into dest, see Aggregate
+func (db *DB) Sum(column string, dest interface{}) (tx *DB) {
+	return db.Aggregate("SUM", column, dest)
+}
+
+// Avg averages column into dest, see Aggregate
+func (db *DB) Avg(column string, dest interface{}) (tx *DB) {
+	return db.Aggregate("AVG", column, dest)
+}
+
+// Min scans the minimum of column into dest, see Aggregate
+func (db *DB) Min(column string, dest interface{}) (tx *DB) {
+	return db.Aggregate("MIN", column, dest)
+}
+
+// Max scans the maximum of column into dest, see Aggregate
+func (db *DB) Max(column string, dest interface{}) (tx *DB) {
+	return db.Aggregate("MAX", column, dest)
+}
+
+// Aggregate scans the result of the aggregate function of column into dest, a pointer to a value, or a pointer to a
+// map keyed by the GROUP BY column for grouped queries. NULL results, e.g. of empty sets, are scanned as zero values
+//
+//	var total float64
+//	db.Model(&Order{}).Where("paid = ?", true).Sum("amount", &total)
+//
+//	totals := map[uint]float64{}
+//	db.Model(&Order{}).Group("user_id").Sum("amount", &totals)
+func (db *DB) Aggregate(function, column string, dest interface{}) (tx *DB) {
+	// scopes may add GROUP BY and ORDER BY clauses checked below
+	tx = db.getInstance().executeScopes()
+
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		tx.AddError(fmt.Errorf("%w: %s requires a pointer destination, got %T", ErrInvalidData, function, dest))
+		return
+	}
+	destValue = destValue.Elem()
+
+	if tx.Statement.Model != nil && tx.Statement.Parse(tx.Statement.Model) == nil {
+		if f := tx.Statement.Schema.LookUpField(column); f != nil {
+			column = f.DBName
+		}
+	}
+
+	var (
+		fields = strings.FieldsFunc(column, utils.IsValidDBNameChar)
+		expr   = clause.Expr{
+			SQL:  function + "(?)",
+			Vars: []interface{}{clause.Column{Name: column, Raw: len(fields) != 1 && !(len(fields) == 3 && fields[1] == ".")}},
+		}
+	)
+
+	if destValue.Kind() == reflect.Map {
+		groupBy, ok := tx.Statement.Clauses["GROUP BY"].Expression.(clause.GroupBy)
+		if !ok || len(groupBy.Columns) != 1 {
+			tx.AddError(fmt.Errorf("%w: %s into a map requires a single GROUP BY column", ErrInvalidData, function))
+			return
+		}
+		expr = clause.Expr{SQL: "?," + expr.SQL, Vars: append([]interface{}{groupBy.Columns[0]}, expr.Vars...)}
+	}
+
+	if selectClause, ok := tx.Statement.Clauses["SELECT"]; ok {
+		defer func() {
+			tx.Statement.Clauses["SELECT"] = selectClause
+		}()
+	} else {
+		defer delete(tx.Statement.Clauses, "SELECT")
+	}
+	tx.Statement.AddClause(clause.Select{Expression: expr})
+
+	if orderByClause, ok := tx.Statement.Clauses["ORDER BY"]; ok {
+		if _, ok := tx.Statement.Clauses["GROUP BY"]; !ok {
+			delete(tx.Statement.Clauses, "ORDER BY")
+			defer func() {
+				tx.Statement.Clauses["ORDER BY"] = orderByClause
+			}()
+		}
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return
+	}
+	defer func() {
+		tx.AddError(rows.Close())
+	}()
+
+	tx.RowsAffected = 0
+	if destValue.Kind() == reflect.Map {
+		if destValue.IsNil() {
+			destValue.Set(reflect.MakeMap(destValue.Type()))
+		}
+
+		for rows.Next() {
+			key, value := reflect.New(destValue.Type().Key()), reflect.New(reflect.PtrTo(destValue.Type().Elem()))
+			if tx.AddError(rows.Scan(key.Interface(), value.Interface())) != nil {
+				return
+			}
+			destValue.SetMapIndex(key.Elem(), nullableElem(value.Elem()))
+			tx.RowsAffected++
+		}
+	} else if rows.Next() {
+		value := reflect.New(reflect.PtrTo(destValue.Type()))
+		if tx.AddError(rows.Scan(value.Interface())) != nil {
+			return
+		}
+		destValue.Set(nullableElem(value.Elem()))
+		tx.RowsAffected++
+	}
+	tx.AddError(rows.Err())
+	return
+}
+
 func (db *DB) Row() *sql.Row {
 	tx := db.getInstance().Set("rows", false)
 	tx = tx.callbacks.Row().Execute(tx)
@@ -542,6 +1076,188 @@ func (db *DB) Pluck(column string, dest interface{}) (tx *DB) {
 	return tx.callbacks.Query().Execute(tx)
 }
 
+// PluckMany queries columns from a model, returning each column in the slice of dests at the same index, NULL values
+// are returned as zero values. E.g.:
+//
+//	var ids []uint
+//	var names []string
+//	db.Model(&User{}).PluckMany([]string{"id", "name"}, &ids, &names)
+func (db *DB) PluckMany(columns []string, dests ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if len(columns) != len(dests) {
+		tx.AddError(fmt.Errorf("%w: %d columns plucked into %d destinations", ErrInvalidData, len(columns), len(dests)))
+		return
+	}
+
+	var (
+		sliceValues = make([]reflect.Value, len(dests))
+		types       = make([]reflect.Type, len(dests))
+	)
+	for idx, dest := range dests {
+		destValue := reflect.ValueOf(dest)
+		if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+			tx.AddError(fmt.Errorf("%w: PluckMany requires pointers to slices, got %T", ErrInvalidData, dest))
+			return
+		}
+		sliceValues[idx] = destValue.Elem()
+		sliceValues[idx].SetLen(0)
+		types[idx] = sliceValues[idx].Type().Elem()
+	}
+
+	return tx.pluckRows(columns, types, func(values []reflect.Value) {
+		for idx, sliceValue := range sliceValues {
+			sliceValue.Set(reflect.Append(sliceValue, values[idx]))
+		}
+	})
+}
+
+// PluckMap queries keyColumn and valueColumn from a model, returning them in the map dest, NULL values are returned as
+// zero values, the last row wins for duplicated keys. E.g.:
+//
+//	var names map[uint]string
+//	db.Model(&User{}).PluckMap("id", "name", &names)
+func (db *DB) PluckMap(keyColumn, valueColumn string, dest interface{}) (tx *DB) {
+	tx = db.getInstance()
+
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Map {
+		tx.AddError(fmt.Errorf("%w: PluckMap requires a pointer to map, got %T", ErrInvalidData, dest))
+		return
+	}
+
+	mapValue := destValue.Elem()
+	if mapValue.IsNil() {
+		mapValue.Set(reflect.MakeMap(mapValue.Type()))
+	}
+
+	types := []reflect.Type{mapValue.Type().Key(), mapValue.Type().Elem()}
+	return tx.pluckRows([]string{keyColumn, valueColumn}, types, func(values []reflect.Value) {
+		mapValue.SetMapIndex(values[0], values[1])
+	})
+}
+
+// pluckRows selects columns and calls fc with each row converted to types, columns of the model's fields are scanned
+// like Find, so serializers and custom types are applied, other columns are scanned into types directly
+func (db *DB) pluckRows(columns []string, types []reflect.Type, fc func(values []reflect.Value)) (tx *DB) {
+	tx = db.getInstance()
+
+	var sch *schema.Schema
+	if tx.Statement.Model != nil && tx.Statement.Parse(tx.Statement.Model) == nil {
+		sch = tx.Statement.Schema
+	}
+
+	selectColumns := make([]clause.Column, len(columns))
+	for idx, column := range columns {
+		if sch != nil {
+			if f := sch.LookUpField(column); f != nil {
+				column = f.DBName
+			}
+		}
+
+		fields := strings.FieldsFunc(column, utils.IsValidDBNameChar)
+		selectColumns[idx] = clause.Column{Name: column, Raw: len(fields) != 1 && !(len(fields) == 3 && fields[1] == ".")}
+	}
+	tx.Statement.AddClause(clause.Select{Distinct: tx.Statement.Distinct, Columns: selectColumns})
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return
+	}
+	defer func() {
+		tx.AddError(rows.Close())
+	}()
+
+	var (
+		values = make([]interface{}, len(columns))
+		fields = make([]*schema.Field, len(columns))
+	)
+	if sch != nil {
+		if rowColumns, err := rows.Columns(); tx.AddError(err) != nil {
+			return
+		} else if len(rowColumns) == len(columns) {
+			var joinFields [][2]*schema.Field
+			if fields, joinFields = lookUpFields(sch, rowColumns, values); len(joinFields) > 0 {
+				for idx, joinField := range joinFields {
+					if joinField[0] != nil {
+						fields[idx] = nil
+					}
+				}
+			}
+		}
+	}
+
+	tx.RowsAffected = 0
+	results := make([]reflect.Value, len(columns))
+	for rows.Next() {
+		for idx, field := range fields {
+			if field != nil {
+				values[idx] = field.NewValuePool.Get()
+			} else {
+				values[idx] = reflect.New(reflect.PtrTo(types[idx])).Interface()
+			}
+		}
+
+		if tx.AddError(rows.Scan(values...)) != nil {
+			return
+		}
+
+		var model reflect.Value
+		for idx, field := range fields {
+			if field == nil {
+				results[idx] = nullableElem(reflect.ValueOf(values[idx]).Elem())
+				continue
+			}
+
+			if !model.IsValid() {
+				model = reflect.New(sch.ModelType).Elem()
+			}
+			err := field.Set(tx.Statement.Context, model, values[idx])
+			field.NewValuePool.Put(values[idx])
+			if tx.AddError(err) != nil {
+				return
+			}
+
+			fieldValue := field.ReflectValueOf(tx.Statement.Context, model)
+			if results[idx], err = convertValue(fieldValue, types[idx]); tx.AddError(err) != nil {
+				return
+			}
+		}
+
+		fc(results)
+		tx.RowsAffected++
+	}
+	tx.AddError(rows.Err())
+	return
+}
+
+// convertValue converts value to typ, dereferencing or referencing pointers, nil pointers are converted to zero values
+func convertValue(value reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	if value.Kind() == reflect.Ptr && !value.Type().AssignableTo(typ) {
+		if value.IsNil() {
+			return reflect.Zero(typ), nil
+		}
+		value = value.Elem()
+	}
+
+	// numbers are convertible to strings as runes, which isn't a conversion of plucked values
+	convertible := func(typ reflect.Type) bool {
+		return value.Type().ConvertibleTo(typ) && (typ.Kind() != reflect.String || value.Kind() == reflect.String ||
+			value.Type() == reflect.TypeOf([]byte{}))
+	}
+
+	switch {
+	case value.Type().AssignableTo(typ):
+		return value, nil
+	case convertible(typ):
+		return value.Convert(typ), nil
+	case typ.Kind() == reflect.Ptr && convertible(typ.Elem()):
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(value.Convert(typ.Elem()))
+		return ptr, nil
+	}
+	return value, fmt.Errorf("%w: can't convert %s to %s", ErrInvalidData, value.Type(), typ)
+}
+
 func (db *DB) ScanRows(rows *sql.Rows, dest interface{}) error {
 	tx := db.getInstance()
 	if err := tx.Statement.Parse(dest); !errors.Is(err, schema.ErrUnsupportedDataType) {
@@ -598,10 +1314,12 @@ func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err er
 				return
 			}
 
+			discardHooks := db.savePointTxHooks()
 			defer func() {
 				// Make sure to rollback when panic, Block error or Commit error
 				if panicked || err != nil {
 					db.RollbackTo(fmt.Sprintf("sp%p", fc))
+					discardHooks()
 				}
 			}()
 		}
@@ -629,6 +1347,78 @@ func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err er
 	return
 }
 
+// TransactionRetry retry options of TransactionWithRetry
+type TransactionRetry struct {
+	// MaxAttempts defaults to 3
+	MaxAttempts int
+	// MinBackoff defaults to 10ms, the backoff doubles each attempt with full jitter
+	MinBackoff time.Duration
+	// MaxBackoff defaults to 1s, or MinBackoff if it's greater
+	MaxBackoff time.Duration
+	// Retryable defaults to IsRetryableError
+	Retryable func(err error) bool
+}
+
+// IsRetryableError returns true for serialization failures, deadlocks and lock timeouts
+func IsRetryableError(err error) bool {
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlock) || errors.Is(err, ErrLockTimeout)
+}
+
+// TransactionWithRetry runs fc in a transaction like Transaction, when it fails with a retryable error the transaction
+// is rolled back and fc is invoked again in a new transaction. Nested transactions are never retried on their own,
+// their errors are returned so the outermost TransactionWithRetry could retry the whole transaction.
+//
+//	db.TransactionWithRetry(func(tx *database.DB) error {
+//		return tx.Model(&account).Update("balance", database.Expr("balance - ?", 100)).Error
+//	}, database.TransactionRetry{MaxAttempts: 5}, &sql.TxOptions{Isolation: sql.LevelSerializable})
+func (db *DB) TransactionWithRetry(fc func(tx *DB) error, retry TransactionRetry, opts ...*sql.TxOptions) (err error) {
+	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
+		return db.Transaction(fc, opts...)
+	}
+
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 3
+	}
+	if retry.MinBackoff <= 0 {
+		retry.MinBackoff = 10 * time.Millisecond
+	}
+	if retry.MaxBackoff < retry.MinBackoff {
+		retry.MaxBackoff = time.Second
+		if retry.MaxBackoff < retry.MinBackoff {
+			retry.MaxBackoff = retry.MinBackoff
+		}
+	}
+	if retry.Retryable == nil {
+		retry.Retryable = IsRetryableError
+	}
+
+	ctx := db.Statement.Context
+	backoff := retry.MinBackoff
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err == nil {
+				return ctxErr
+			}
+			return err
+		}
+
+		if err = db.translateError(db.Transaction(fc, opts...)); err == nil || attempt >= retry.MaxAttempts || !retry.Retryable(err) {
+			return err
+		}
+
+		db.Logger.Warn(ctx, "retrying transaction after attempt %d failed: %v", attempt, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(rand.Int63n(int64(backoff)) + 1)):
+		}
+
+		if backoff *= 2; backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
 // Begin begins a transaction with any transaction options opts
 func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 	var (
@@ -653,6 +1443,8 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 
 	if err != nil {
 		tx.AddError(err)
+	} else {
+		tx.Statement.Settings.Store(TxHooksKey, &transactionHooks{})
 	}
 
 	return tx
@@ -661,7 +1453,13 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 // Commit commits the changes in a transaction
 func (db *DB) Commit() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil && !reflect.ValueOf(committer).IsNil() {
-		db.AddError(committer.Commit())
+		hooks := db.popTxHooks()
+		if err := committer.Commit(); err != nil {
+			db.AddError(err)
+			hooks.run(hooks.afterRollback)
+		} else {
+			hooks.run(hooks.afterCommit)
+		}
 	} else {
 		db.AddError(ErrInvalidTransaction)
 	}
@@ -672,7 +1470,9 @@ func (db *DB) Commit() *DB {
 func (db *DB) Rollback() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
 		if !reflect.ValueOf(committer).IsNil() {
+			hooks := db.popTxHooks()
 			db.AddError(committer.Rollback())
+			hooks.run(hooks.afterRollback)
 		}
 	} else {
 		db.AddError(ErrInvalidTransaction)
@@ -698,6 +1498,103 @@ func (db *DB) RollbackTo(name string) *DB {
 	return db
 }
 
+// AfterCommit registers fc to be called after the current transaction is committed, hooks registered in nested
+// transactions are deferred to the outermost commit. fc is called immediately when db isn't in a transaction
+func (db *DB) AfterCommit(fc func()) *DB {
+	if hooks := db.txHooks(); hooks != nil {
+		hooks.mux.Lock()
+		hooks.afterCommit = append(hooks.afterCommit, fc)
+		hooks.mux.Unlock()
+	} else {
+		fc()
+	}
+	return db
+}
+
+// AfterRollback registers fc to be called after the current transaction is rolled back, or after the nested
+// transaction fc is registered in is rolled back to its savepoint. fc is ignored when db isn't in a transaction
+func (db *DB) AfterRollback(fc func()) *DB {
+	if hooks := db.txHooks(); hooks != nil {
+		hooks.mux.Lock()
+		hooks.afterRollback = append(hooks.afterRollback, fc)
+		hooks.mux.Unlock()
+	}
+	return db
+}
+
+// TxHooksKey Statement.Settings key of transaction hooks, set by Begin, the hooks are shared by statements of the
+// transaction and dropped with them
+const TxHooksKey = "database:transaction_hooks"
+
+type transactionHooks struct {
+	mux           sync.Mutex
+	finished      bool
+	afterCommit   []func()
+	afterRollback []func()
+}
+
+func (hooks *transactionHooks) run(fcs []func()) {
+	for _, fc := range fcs {
+		fc()
+	}
+}
+
+// txHooks returns hooks of the current transaction, nil if db isn't in a transaction or it finished
+func (db *DB) txHooks() *transactionHooks {
+	if committer, ok := db.Statement.ConnPool.(TxCommitter); !ok || committer == nil {
+		return nil
+	}
+
+	// transactions of the connection pool not begun by Begin, hooks are run by Commit or Rollback of db
+	v, _ := db.Statement.Settings.LoadOrStore(TxHooksKey, &transactionHooks{})
+	hooks := v.(*transactionHooks)
+
+	hooks.mux.Lock()
+	defer hooks.mux.Unlock()
+	if hooks.finished {
+		return nil
+	}
+	return hooks
+}
+
+// popTxHooks finishes and returns hooks of the current transaction
+func (db *DB) popTxHooks() *transactionHooks {
+	hooks := &transactionHooks{}
+	if v, ok := db.Statement.Settings.Load(TxHooksKey); ok {
+		current := v.(*transactionHooks)
+		current.mux.Lock()
+		if !current.finished {
+			current.finished = true
+			hooks.afterCommit, hooks.afterRollback = current.afterCommit, current.afterRollback
+			current.afterCommit, current.afterRollback = nil, nil
+		}
+		current.mux.Unlock()
+	}
+	return hooks
+}
+
+// savePointTxHooks returns a func to be called when the transaction is rolled back to a savepoint created now, it
+// discards AfterCommit hooks registered since then and calls the AfterRollback ones
+func (db *DB) savePointTxHooks() func() {
+	hooks := db.txHooks()
+	if hooks == nil {
+		return func() {}
+	}
+
+	hooks.mux.Lock()
+	commits, rollbacks := len(hooks.afterCommit), len(hooks.afterRollback)
+	hooks.mux.Unlock()
+
+	return func() {
+		hooks.mux.Lock()
+		discarded := append([]func(){}, hooks.afterRollback[rollbacks:]...)
+		hooks.afterCommit = hooks.afterCommit[:commits]
+		hooks.afterRollback = hooks.afterRollback[:rollbacks]
+		hooks.mux.Unlock()
+		hooks.run(discarded)
+	}
+}
+
 // Exec executes raw sql
 func (db *DB) Exec(sql string, values ...interface{}) (tx *DB) {
 	tx = db.getInstance()
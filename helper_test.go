@@ -0,0 +1,30 @@
+package database_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/logger"
+)
+
+// openDB opens a sqlite database of a temp file and migrates models
+func openDB(t *testing.T, models ...interface{}) *database.DB {
+	dsn := filepath.Join(t.TempDir(), "database.db") + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	db, err := database.Open(sqlite.Open(dsn), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	return db
+}
@@ -20,6 +20,17 @@ type Dialector interface {
 	Explain(sql string, vars ...interface{}) string
 }
 
+// ErrorTranslator translate driver errors to portable errors, e.g. ErrDuplicatedKey, see TranslatedError
+type ErrorTranslator interface {
+	Translate(err error) error
+}
+
+// DataTypeSerializer serializers of the dialect's data types, e.g. schema.Array, fields of them are serialized with
+// the returned serializers in databases opened with the dialector only
+type DataTypeSerializer interface {
+	DataTypeSerializer(schema.DataType) (schema.SerializerInterface, bool)
+}
+
 // Plugin Database plugin interface
 type Plugin interface {
 	Name() string
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/driver005/database/schema"
+)
+
+// DefaultIterChunkSize default count of rows scanned ahead by Iterator
+const DefaultIterChunkSize = 100
+
+// Iterator streams query results, rows are scanned chunk by chunk, preloads and AfterFind hooks run once per chunk
+type Iterator[T any] struct {
+	db         *DB
+	ctx        context.Context
+	rows       *sql.Rows
+	preloads   map[string][]interface{}
+	afterFind  bool
+	fields     []*schema.Field
+	joinFields [][2]*schema.Field
+	values     []interface{}
+	chunk      []T
+	chunkSize  int
+	index      int
+	done       bool
+	err        error
+
+	closeOnce sync.Once
+	closing   chan struct{}
+	finished  chan struct{}
+}
+
+// Iter executes the query and returns an iterator of its results, the rows have to be closed with Close unless Next
+// returned false, they are closed when the context is canceled as well
+//
+//	iter := database.Iter[User](db.WithContext(ctx).Preload("Orders").Where("active = ?", true), 500)
+//	defer iter.Close()
+//
+//	for iter.Next() {
+//		user := iter.Value()
+//		// ...
+//	}
+//	if err := iter.Err(); err != nil {
+//		// ...
+//	}
+func Iter[T any](db *DB, chunkSize ...int) *Iterator[T] {
+	it := &Iterator[T]{chunkSize: DefaultIterChunkSize, index: -1, closing: make(chan struct{})}
+	if len(chunkSize) > 0 && chunkSize[0] > 0 {
+		it.chunkSize = chunkSize[0]
+	}
+
+	tx := db.getInstance()
+	if tx.Statement.Model == nil {
+		tx.Statement.Model = new(T)
+	}
+	it.ctx, it.preloads = tx.Statement.Context, tx.Statement.Preloads
+
+	if it.rows, it.err = tx.Rows(); it.err != nil {
+		it.done = true
+		return it
+	}
+
+	// the iterator session scans rows into T, preloads and hooks run with it as well
+	it.db = tx.Session(&Session{NewDB: true}).getInstance()
+	it.db.Statement.SkipHooks = tx.Statement.SkipHooks
+	if err := it.db.Statement.Parse(new(T)); err != nil && !errors.Is(err, schema.ErrUnsupportedDataType) {
+		it.err = err
+	}
+
+	columns, err := it.rows.Columns()
+	if err != nil && it.err == nil {
+		it.err = err
+	}
+	it.values = make([]interface{}, len(columns))
+	it.fields = make([]*schema.Field, len(columns))
+
+	if sch := it.db.Statement.Schema; sch != nil {
+		it.afterFind = sch.AfterFind && !it.db.Statement.SkipHooks
+		// single column scanned into a scanner or time like Pluck
+		if _, isScanner := interface{}(new(T)).(sql.Scanner); len(columns) > 1 ||
+			!(isScanner || sch.ModelType.ConvertibleTo(schema.TimeReflectType)) {
+			it.fields, it.joinFields = lookUpFields(sch, columns, it.values)
+		}
+	}
+
+	if it.err != nil {
+		it.close()
+	}
+	return it
+}
+
+// Next prepares the next value, returns false when rows are exhausted or failed
+func (it *Iterator[T]) Next() bool {
+	if it.index++; it.index < len(it.chunk) {
+		return true
+	}
+
+	if it.done {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		it.close()
+		return false
+	}
+
+	it.fetch()
+	return it.index < len(it.chunk)
+}
+
+// Value returns the current value
+func (it *Iterator[T]) Value() T {
+	return it.chunk[it.index]
+}
+
+// Err returns the error happened when iterating
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close closes the rows, when used with Chan it stops sending and waits for the rows to be closed
+func (it *Iterator[T]) Close() error {
+	it.closeOnce.Do(func() {
+		close(it.closing)
+	})
+
+	if it.finished != nil {
+		<-it.finished
+		return nil
+	}
+
+	it.chunk = it.chunk[:0]
+	return it.close()
+}
+
+// Chan sends values to the returned channel in a new goroutine, buffered with size values, sending blocks until the
+// receiver catches up. The channel is closed when rows are exhausted or failed, the context is canceled or Close is
+// called, check Err afterwards. Next mustn't be called once Chan is used
+func (it *Iterator[T]) Chan(size int) <-chan T {
+	ch := make(chan T, size)
+	it.finished = make(chan struct{})
+
+	go func() {
+		defer close(it.finished)
+		defer close(ch)
+		defer it.close()
+
+		for it.Next() {
+			select {
+			case ch <- it.Value():
+			case <-it.closing:
+				return
+			case <-it.ctx.Done():
+				it.err = it.ctx.Err()
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// fetch scans the next chunk of rows
+func (it *Iterator[T]) fetch() {
+	it.chunk, it.index = it.chunk[:0], 0
+	it.db.RowsAffected = 0
+
+	for len(it.chunk) < it.chunkSize {
+		if !it.rows.Next() {
+			it.err = it.rows.Err()
+			it.close()
+			break
+		}
+
+		var value T
+		it.chunk = append(it.chunk, value)
+		reflectValue := reflect.ValueOf(&it.chunk[len(it.chunk)-1]).Elem()
+		if reflectValue.Kind() == reflect.Ptr {
+			reflectValue.Set(reflect.New(reflectValue.Type().Elem()))
+			reflectValue = reflectValue.Elem()
+		}
+
+		it.db.scanIntoStruct(it.rows, reflectValue, it.values, it.fields, it.joinFields)
+		if it.db.Error != nil {
+			it.err = it.db.Error
+			it.close()
+			break
+		}
+	}
+
+	if it.err == nil && len(it.chunk) > 0 && (len(it.preloads) > 0 || it.afterFind) {
+		// rows are scanned already, only preloads and after query callbacks are executed
+		tx := it.db.Session(&Session{NewDB: true}).InstanceSet("database:scanned", true)
+		tx.Statement.Dest, tx.Statement.Preloads = &it.chunk, it.preloads
+		tx.Statement.SkipHooks = it.db.Statement.SkipHooks
+		tx.RowsAffected = int64(len(it.chunk))
+
+		tx = tx.callbacks.Query().Execute(tx)
+		if tx.Error != nil {
+			it.err = tx.Error
+			it.close()
+		}
+	}
+
+	if it.err != nil {
+		it.chunk = it.chunk[:0]
+	}
+}
+
+func (it *Iterator[T]) close() error {
+	it.done = true
+	if it.rows == nil {
+		return nil
+	}
+	return it.rows.Close()
+}
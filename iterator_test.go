@@ -0,0 +1,162 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/driver005/database"
+)
+
+type Author struct {
+	ID     uint
+	Name   string
+	Books  []Book
+	Loaded bool `database:"-"`
+}
+
+func (a *Author) AfterFind(*database.DB) error {
+	a.Loaded = true
+	return nil
+}
+
+type Book struct {
+	ID       uint
+	AuthorID uint
+	Title    string
+}
+
+func createAuthors(t *testing.T, db *database.DB, count int) {
+	for i := 0; i < count; i++ {
+		author := Author{Name: fmt.Sprintf("author%d", i), Books: []Book{{Title: fmt.Sprintf("book%d", i)}}}
+		if err := db.Create(&author).Error; err != nil {
+			t.Fatalf("no error should happen when create author, got %v", err)
+		}
+	}
+}
+
+func TestIter(t *testing.T) {
+	db := openDB(t, &Author{}, &Book{})
+	createAuthors(t, db, 5)
+
+	iter := database.Iter[Author](db.Preload("Books").Order("id"), 2)
+	defer iter.Close()
+
+	var names []string
+	for iter.Next() {
+		author := iter.Value()
+		names = append(names, author.Name)
+
+		if !author.Loaded || len(author.Books) != 1 || author.Books[0].AuthorID != author.ID {
+			t.Errorf("preloads and hooks should run for every chunk, got %+v", author)
+		}
+	}
+
+	if err := iter.Err(); err != nil || fmt.Sprint(names) != "[author0 author1 author2 author3 author4]" {
+		t.Errorf("all authors should be iterated, got %v, error %v", names, err)
+	}
+
+	if iter.Next() {
+		t.Errorf("exhausted iterator shouldn't return values")
+	}
+
+	pointers := database.Iter[*Author](db.Where("name <> ?", "author0").Order("id desc"))
+	defer pointers.Close()
+
+	names = nil
+	for pointers.Next() {
+		names = append(names, pointers.Value().Name)
+	}
+
+	if err := pointers.Err(); err != nil || fmt.Sprint(names) != "[author4 author3 author2 author1]" {
+		t.Errorf("authors matching conditions should be iterated, got %v, error %v", names, err)
+	}
+
+	var count int
+	for titles := database.Iter[string](db.Model(&Book{}).Select("title")); titles.Next(); count++ {
+		if titles.Value() != fmt.Sprintf("book%d", count) {
+			t.Errorf("single columns should be scanned into values, got %v", titles.Value())
+		}
+	}
+
+	if count != 5 {
+		t.Errorf("all titles should be iterated, got %v", count)
+	}
+}
+
+func TestIterClose(t *testing.T) {
+	db := openDB(t, &Author{}, &Book{})
+	createAuthors(t, db, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iter := database.Iter[Author](db.WithContext(ctx).Order("id"), 1)
+	if !iter.Next() || iter.Value().Name != "author0" {
+		t.Fatalf("first author should be iterated, got %v", iter.Err())
+	}
+
+	cancel()
+	if iter.Next() || !errors.Is(iter.Err(), context.Canceled) {
+		t.Errorf("iterator should be stopped when canceled, got %v", iter.Err())
+	}
+
+	iter = database.Iter[Author](db.Table("unknown"))
+	if iter.Next() || iter.Err() == nil {
+		t.Errorf("query errors should be returned by Err")
+	}
+
+	if err := iter.Close(); err != nil {
+		t.Errorf("no error should happen when close, got %v", err)
+	}
+
+	// rows are closed, so the database can be written to again
+	iter = database.Iter[Author](db.Order("id"))
+	if !iter.Next() {
+		t.Fatalf("first author should be iterated, got %v", iter.Err())
+	}
+
+	if err := iter.Close(); err != nil || iter.Next() {
+		t.Errorf("closed iterator shouldn't return values, got error %v", err)
+	}
+}
+
+func TestIterChan(t *testing.T) {
+	db := openDB(t, &Author{}, &Book{})
+	createAuthors(t, db, 5)
+
+	iter := database.Iter[Author](db.Order("id"), 2)
+	var names []string
+	for author := range iter.Chan(1) {
+		names = append(names, author.Name)
+	}
+
+	if err := iter.Err(); err != nil || len(names) != 5 {
+		t.Errorf("all authors should be sent, got %v, error %v", names, err)
+	}
+
+	iter = database.Iter[Author](db.Order("id"), 2)
+	ch := iter.Chan(0)
+	if author := <-ch; author.Name != "author0" {
+		t.Errorf("first author should be sent, got %+v", author)
+	}
+
+	if err := iter.Close(); err != nil {
+		t.Errorf("no error should happen when close, got %v", err)
+	}
+
+	for range ch {
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iter = database.Iter[Author](db.WithContext(ctx).Order("id"), 2)
+	ch = iter.Chan(0)
+	<-ch
+	cancel()
+
+	for range ch {
+	}
+
+	if !errors.Is(iter.Err(), context.Canceled) {
+		t.Errorf("sending should be stopped when canceled, got %v", iter.Err())
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import "errors"
+
+var (
+	// ErrInvalidVersion migration version must be positive
+	ErrInvalidVersion = errors.New("migration version must be greater than zero")
+	// ErrDuplicatedVersion migration version registered twice
+	ErrDuplicatedVersion = errors.New("duplicated migration version")
+	// ErrMissingUp migration without Up function
+	ErrMissingUp = errors.New("migration up function required")
+	// ErrIrreversible migration without Down function can't be rolled back
+	ErrIrreversible = errors.New("migration is irreversible")
+	// ErrUnknownVersion version isn't registered
+	ErrUnknownVersion = errors.New("unknown migration version")
+	// ErrNoAppliedMigrations nothing to rollback
+	ErrNoAppliedMigrations = errors.New("no applied migrations")
+	// ErrLockTimeout failed to acquire the migrations lock in time
+	ErrLockTimeout = errors.New("timeout acquiring migrations lock")
+)
@@ -0,0 +1,205 @@
+package migrations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+)
+
+// Locker prevents concurrent migration runs, TryLock and Unlock are called on the same connection
+type Locker interface {
+	TryLock(db *database.DB) (bool, error)
+	Unlock(db *database.DB) error
+}
+
+// DefaultLocker returns an advisory locker for postgres and mysql, other dialects lock with a table row
+func DefaultLocker(db *database.DB, name string) Locker {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return PostgresLocker{Key: lockKey(name)}
+	case "mysql":
+		return MySQLLocker{Name: name}
+	default:
+		return &TableLocker{Table: name + "_lock"}
+	}
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// PostgresLocker session level advisory lock
+type PostgresLocker struct {
+	Key int64
+}
+
+func (l PostgresLocker) TryLock(db *database.DB) (locked bool, err error) {
+	err = db.Raw("SELECT pg_try_advisory_lock(?)", l.Key).Row().Scan(&locked)
+	return
+}
+
+func (l PostgresLocker) Unlock(db *database.DB) error {
+	return db.Exec("SELECT pg_advisory_unlock(?)", l.Key).Error
+}
+
+// MySQLLocker named lock with GET_LOCK
+type MySQLLocker struct {
+	Name string
+}
+
+func (l MySQLLocker) TryLock(db *database.DB) (bool, error) {
+	var locked *int64
+	if err := db.Raw("SELECT GET_LOCK(?, 0)", l.Name).Row().Scan(&locked); err != nil {
+		return false, err
+	}
+	return locked != nil && *locked == 1, nil
+}
+
+func (l MySQLLocker) Unlock(db *database.DB) error {
+	return db.Exec("SELECT RELEASE_LOCK(?)", l.Name).Error
+}
+
+// TableLocker lock by inserting a single row into Table, works with any dialect. The row is a lease of TTL renewed
+// by a heartbeat while locked, so locks of crashed runs expire and are taken over, TTL must exceed the clock skew of
+// replicas as expiration times of replicas are compared
+type TableLocker struct {
+	Table string
+	// TTL lease of the lock, renewed every TTL/3, defaults to 1 minute
+	TTL time.Duration
+
+	mu    sync.Mutex
+	owner string
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+type migrationLock struct {
+	ID        int    `database:"primaryKey;autoIncrement:false"`
+	Owner     string `database:"size:64"`
+	LockedAt  time.Time
+	ExpiresAt time.Time
+}
+
+func (l *TableLocker) ttl() time.Duration {
+	if l.TTL > 0 {
+		return l.TTL
+	}
+	return time.Minute
+}
+
+func (l *TableLocker) TryLock(db *database.DB) (bool, error) {
+	if err := l.createTable(db); err != nil {
+		return false, err
+	}
+
+	owner, err := newOwner()
+	if err != nil {
+		return false, err
+	}
+
+	now := db.NowFunc()
+	lock := migrationLock{ID: 1, Owner: owner, LockedAt: now, ExpiresAt: now.Add(l.ttl())}
+	result := db.Table(l.Table).Clauses(clause.OnConflict{DoNothing: true}).Create(&lock)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		// the heartbeat of the lock's run stopped, e.g. the replica crashed
+		result = db.Table(l.Table).Where("id = ? AND expires_at < ?", 1, now).Updates(map[string]interface{}{
+			"owner": owner, "locked_at": now, "expires_at": lock.ExpiresAt,
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return false, result.Error
+		}
+		db.Logger.Warn(db.Statement.Context, "took over expired migrations lock %s", l.Table)
+	}
+
+	l.heartbeat(db, owner)
+	return true, nil
+}
+
+func (l *TableLocker) Unlock(db *database.DB) error {
+	l.mu.Lock()
+	owner, stop, done := l.owner, l.stop, l.done
+	l.owner, l.stop, l.done = "", nil, nil
+	l.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	// the lock may have been taken over if the heartbeat failed
+	return db.Table(l.Table).Where("owner = ?", owner).Delete(&migrationLock{ID: 1}).Error
+}
+
+func (l *TableLocker) createTable(db *database.DB) error {
+	tx := db.Table(l.Table)
+	if !tx.Migrator().HasTable(l.Table) {
+		// another replica may create it at the same time
+		if err := tx.Migrator().CreateTable(&migrationLock{}); err != nil && !tx.Migrator().HasTable(l.Table) {
+			return err
+		}
+		return nil
+	}
+
+	// lock tables created without leases
+	for _, name := range []string{"Owner", "ExpiresAt"} {
+		if !tx.Migrator().HasColumn(&migrationLock{}, name) {
+			if err := tx.Migrator().AddColumn(&migrationLock{}, name); err != nil && !tx.Migrator().HasColumn(&migrationLock{}, name) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// heartbeat renews the lease of owner until Unlock, the locked connection is busy running migrations, so the lease
+// is renewed with the connection pool
+func (l *TableLocker) heartbeat(db *database.DB, owner string) {
+	tx := db.Session(&database.Session{NewDB: true, Context: context.Background()})
+	tx.Statement.ConnPool = db.ConnPool
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	l.mu.Lock()
+	l.owner, l.stop, l.done = owner, stop, done
+	l.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(l.ttl() / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				result := tx.Table(l.Table).Where("id = ? AND owner = ?", 1, owner).Update("expires_at", tx.NowFunc().Add(l.ttl()))
+				if result.Error != nil {
+					tx.Logger.Warn(tx.Statement.Context, "failed to renew migrations lock %s: %v", l.Table, result.Error)
+				} else if result.RowsAffected == 0 {
+					tx.Logger.Warn(tx.Statement.Context, "lost migrations lock %s, it expired and was taken over", l.Table)
+				}
+			}
+		}
+	}()
+}
+
+func newOwner() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
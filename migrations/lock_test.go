@@ -0,0 +1,131 @@
+package migrations_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/migrations"
+)
+
+type lock struct {
+	ID        int
+	Owner     string
+	ExpiresAt time.Time
+}
+
+func openDB(t *testing.T) *database.DB {
+	dsn := filepath.Join(t.TempDir(), "migrations.db") + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	db, err := database.Open(sqlite.Open(dsn), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func currentLock(t *testing.T, db *database.DB) (result lock) {
+	if err := db.Table("migrations_lock").Take(&result).Error; err != nil {
+		t.Fatalf("failed to find lock, got error %v", err)
+	}
+	return
+}
+
+func TestTableLocker(t *testing.T) {
+	db := openDB(t)
+	locker1, locker2 := &migrations.TableLocker{Table: "migrations_lock"}, &migrations.TableLocker{Table: "migrations_lock"}
+
+	if locked, err := locker1.TryLock(db); err != nil || !locked {
+		t.Fatalf("locker1 should be locked, got %v, error %v", locked, err)
+	}
+
+	if locked, err := locker2.TryLock(db); err != nil || locked {
+		t.Fatalf("locker2 shouldn't be locked while locker1 holds the lock, got %v, error %v", locked, err)
+	}
+
+	// unlocking without holding the lock keeps the lock of others
+	if err := locker2.Unlock(db); err != nil {
+		t.Fatalf("no error should happen when unlock, got %v", err)
+	}
+
+	if locked, _ := locker2.TryLock(db); locked {
+		t.Fatalf("locker2 shouldn't be locked after unlocking it")
+	}
+
+	if err := locker1.Unlock(db); err != nil {
+		t.Fatalf("no error should happen when unlock, got %v", err)
+	}
+
+	if locked, err := locker2.TryLock(db); err != nil || !locked {
+		t.Fatalf("locker2 should be locked after locker1 unlocked, got %v, error %v", locked, err)
+	}
+	locker2.Unlock(db)
+}
+
+func TestTableLockerTakeOver(t *testing.T) {
+	db := openDB(t)
+	locker := &migrations.TableLocker{Table: "migrations_lock"}
+
+	if locked, err := locker.TryLock(db); err != nil || !locked {
+		t.Fatalf("locker should be locked, got %v, error %v", locked, err)
+	}
+	locker.Unlock(db)
+
+	// a live lock of another replica
+	db.Table("migrations_lock").Create(map[string]interface{}{
+		"id": 1, "owner": "replica", "locked_at": time.Now(), "expires_at": time.Now().Add(time.Minute),
+	})
+
+	if locked, err := locker.TryLock(db); err != nil || locked {
+		t.Fatalf("live locks shouldn't be taken over, got %v, error %v", locked, err)
+	}
+
+	// the replica crashed, its lease expired
+	db.Table("migrations_lock").Where("id = ?", 1).Update("expires_at", time.Now().Add(-time.Second))
+
+	if locked, err := locker.TryLock(db); err != nil || !locked {
+		t.Fatalf("expired locks should be taken over, got %v, error %v", locked, err)
+	}
+
+	if result := currentLock(t, db); result.Owner == "replica" {
+		t.Errorf("owner of the lock should be changed, got %v", result.Owner)
+	}
+
+	if err := locker.Unlock(db); err != nil {
+		t.Fatalf("no error should happen when unlock, got %v", err)
+	}
+
+	var count int64
+	if db.Table("migrations_lock").Count(&count); count != 0 {
+		t.Errorf("lock should be released, got %v", count)
+	}
+}
+
+func TestTableLockerHeartbeat(t *testing.T) {
+	db := openDB(t)
+	locker := &migrations.TableLocker{Table: "migrations_lock", TTL: 150 * time.Millisecond}
+
+	if locked, err := locker.TryLock(db); err != nil || !locked {
+		t.Fatalf("locker should be locked, got %v, error %v", locked, err)
+	}
+	defer locker.Unlock(db)
+
+	time.Sleep(400 * time.Millisecond)
+
+	if result := currentLock(t, db); !result.ExpiresAt.After(time.Now()) {
+		t.Errorf("lease should be renewed, expires at %v", result.ExpiresAt)
+	}
+
+	other := &migrations.TableLocker{Table: "migrations_lock", TTL: 150 * time.Millisecond}
+	if locked, _ := other.TryLock(db); locked {
+		t.Errorf("renewed locks shouldn't be taken over")
+	}
+}
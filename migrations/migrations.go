@@ -0,0 +1,333 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/driver005/database"
+)
+
+// Migration versioned migration, Up and Down receive a transaction unless DisableTransaction is set
+//
+//	migrations.Migration{
+//		Version: 20221201120000,
+//		Name:    "create_users",
+//		Up: func(tx *database.DB) error {
+//			return tx.Migrator().CreateTable(&User{})
+//		},
+//		Down: func(tx *database.DB) error {
+//			return tx.Migrator().DropTable(&User{})
+//		},
+//	}
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *database.DB) error
+	Down    func(tx *database.DB) error
+	// DisableTransaction run without transaction, e.g. CREATE INDEX CONCURRENTLY
+	DisableTransaction bool
+}
+
+// SchemaMigration applied migration record
+type SchemaMigration struct {
+	Version   int64 `database:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// Status migration status returned by Migrator.Status
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Missing applied in database but not registered
+	Missing bool
+}
+
+// Options migrator options
+type Options struct {
+	// TableName tracking table name, defaults to `schema_migrations`
+	TableName string
+	// Locker defaults to DefaultLocker
+	Locker Locker
+	// LockTimeout how long to wait for another replica's run, zero waits forever
+	LockTimeout time.Duration
+	// LockRetryInterval defaults to 1 second
+	LockRetryInterval time.Duration
+}
+
+// Migrator runs versioned migrations and records them in the tracking table
+type Migrator struct {
+	db         *database.DB
+	options    Options
+	migrations []*Migration
+}
+
+// New returns a migrator for migrations
+func New(db *database.DB, options *Options, migrations ...*Migration) (*Migrator, error) {
+	m := &Migrator{db: db}
+	if options != nil {
+		m.options = *options
+	}
+
+	if m.options.TableName == "" {
+		m.options.TableName = "schema_migrations"
+	}
+
+	if m.options.Locker == nil {
+		m.options.Locker = DefaultLocker(db, m.options.TableName)
+	}
+
+	if m.options.LockRetryInterval <= 0 {
+		m.options.LockRetryInterval = time.Second
+	}
+
+	return m, m.Register(migrations...)
+}
+
+// Register register migrations
+func (m *Migrator) Register(migrations ...*Migration) error {
+	for _, migration := range migrations {
+		if migration.Version <= 0 {
+			return fmt.Errorf("%w: %d %s", ErrInvalidVersion, migration.Version, migration.Name)
+		}
+
+		if migration.Up == nil {
+			return fmt.Errorf("%w: %d %s", ErrMissingUp, migration.Version, migration.Name)
+		}
+
+		if m.find(migration.Version) != nil {
+			return fmt.Errorf("%w: %d", ErrDuplicatedVersion, migration.Version)
+		}
+
+		m.migrations = append(m.migrations, migration)
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return nil
+}
+
+// Migrate apply all pending migrations
+func (m *Migrator) Migrate() error {
+	return m.run(func(db *database.DB, applied map[int64]SchemaMigration) error {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; !ok {
+				if err := m.up(db, migration); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateTo apply or rollback migrations until version is the last applied one
+func (m *Migrator) MigrateTo(version int64) error {
+	if m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.run(func(db *database.DB, applied map[int64]SchemaMigration) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if migration := m.migrations[i]; migration.Version > version {
+				if _, ok := applied[migration.Version]; ok {
+					if err := m.down(db, migration); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+
+			if _, ok := applied[migration.Version]; !ok {
+				if err := m.up(db, migration); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// RollbackLast rollback the last applied migration
+func (m *Migrator) RollbackLast() error {
+	return m.run(func(db *database.DB, applied map[int64]SchemaMigration) error {
+		var last *SchemaMigration
+		for _, record := range applied {
+			if record := record; last == nil || record.Version > last.Version {
+				last = &record
+			}
+		}
+
+		if last == nil {
+			return ErrNoAppliedMigrations
+		}
+
+		migration := m.find(last.Version)
+		if migration == nil {
+			return fmt.Errorf("%w: %d %s", ErrUnknownVersion, last.Version, last.Name)
+		}
+		return m.down(db, migration)
+	})
+}
+
+// Status returns registered and applied migrations ordered by version
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied(m.db.Session(&database.Session{NewDB: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		record := record
+		statuses = append(statuses, Status{
+			Version: record.Version, Name: record.Name, Applied: true, AppliedAt: &record.AppliedAt, Missing: true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+// run fc on a single connection while holding the lock
+func (m *Migrator) run(fc func(db *database.DB, applied map[int64]SchemaMigration) error) error {
+	return m.db.Connection(func(conn *database.DB) (err error) {
+		db := conn.Session(&database.Session{NewDB: true})
+		if err = m.lock(db); err != nil {
+			return err
+		}
+
+		defer func() {
+			if unlockErr := m.options.Locker.Unlock(db); err == nil {
+				err = unlockErr
+			}
+		}()
+
+		if err = m.createTable(db); err != nil {
+			return err
+		}
+
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+		return fc(db, applied)
+	})
+}
+
+func (m *Migrator) lock(db *database.DB) error {
+	var deadline time.Time
+	if m.options.LockTimeout > 0 {
+		deadline = time.Now().Add(m.options.LockTimeout)
+	}
+
+	for {
+		locked, err := m.options.Locker.TryLock(db)
+		if err != nil || locked {
+			return err
+		}
+
+		if !deadline.IsZero() && time.Now().Add(m.options.LockRetryInterval).After(deadline) {
+			return ErrLockTimeout
+		}
+
+		db.Logger.Info(db.Statement.Context, "waiting for migrations lock %s", m.options.TableName)
+		select {
+		case <-db.Statement.Context.Done():
+			return db.Statement.Context.Err()
+		case <-time.After(m.options.LockRetryInterval):
+		}
+	}
+}
+
+func (m *Migrator) createTable(db *database.DB) error {
+	tx := db.Table(m.options.TableName)
+	if tx.Migrator().HasTable(m.options.TableName) {
+		return nil
+	}
+	return tx.Migrator().CreateTable(&SchemaMigration{})
+}
+
+func (m *Migrator) applied(db *database.DB) (map[int64]SchemaMigration, error) {
+	results := map[int64]SchemaMigration{}
+	if !db.Migrator().HasTable(m.options.TableName) {
+		return results, nil
+	}
+
+	var records []SchemaMigration
+	if err := db.Table(m.options.TableName).Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		results[record.Version] = record
+	}
+	return results, nil
+}
+
+func (m *Migrator) up(db *database.DB, migration *Migration) error {
+	db.Logger.Info(db.Statement.Context, "migrating %d %s", migration.Version, migration.Name)
+
+	return m.transaction(db, migration, func(tx *database.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+
+		return tx.Table(m.options.TableName).Create(&SchemaMigration{
+			Version: migration.Version, Name: migration.Name, AppliedAt: tx.NowFunc(),
+		}).Error
+	})
+}
+
+func (m *Migrator) down(db *database.DB, migration *Migration) error {
+	if migration.Down == nil {
+		return fmt.Errorf("%w: %d %s", ErrIrreversible, migration.Version, migration.Name)
+	}
+
+	db.Logger.Info(db.Statement.Context, "rolling back %d %s", migration.Version, migration.Name)
+
+	return m.transaction(db, migration, func(tx *database.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return fmt.Errorf("rollback %d %s: %w", migration.Version, migration.Name, err)
+		}
+
+		return tx.Table(m.options.TableName).Delete(&SchemaMigration{Version: migration.Version}).Error
+	})
+}
+
+func (m *Migrator) transaction(db *database.DB, migration *Migration, fc func(tx *database.DB) error) error {
+	if migration.DisableTransaction {
+		return fc(db.Session(&database.Session{}))
+	}
+	return db.Transaction(fc)
+}
@@ -0,0 +1,201 @@
+package migrations_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/migrations"
+)
+
+type Product struct {
+	ID   uint
+	Name string
+}
+
+type Order struct {
+	ID        uint
+	ProductID uint
+}
+
+func registered(ups *int32) []*migrations.Migration {
+	return []*migrations.Migration{
+		{
+			Version: 1,
+			Name:    "create_products",
+			Up: func(tx *database.DB) error {
+				atomic.AddInt32(ups, 1)
+				return tx.Migrator().CreateTable(&Product{})
+			},
+			Down: func(tx *database.DB) error {
+				return tx.Migrator().DropTable(&Product{})
+			},
+		},
+		{
+			Version: 2,
+			Name:    "create_orders",
+			Up: func(tx *database.DB) error {
+				atomic.AddInt32(ups, 1)
+				return tx.Migrator().CreateTable(&Order{})
+			},
+			Down: func(tx *database.DB) error {
+				return tx.Migrator().DropTable(&Order{})
+			},
+		},
+	}
+}
+
+func applied(t *testing.T, m *migrations.Migrator) (versions []int64) {
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatalf("no error should happen when get status, got %v", err)
+	}
+
+	for _, status := range statuses {
+		if status.Applied {
+			versions = append(versions, status.Version)
+		}
+	}
+	return
+}
+
+func TestMigrate(t *testing.T) {
+	var ups int32
+	db := openDB(t)
+	m, err := migrations.New(db, nil, registered(&ups)...)
+	if err != nil {
+		t.Fatalf("no error should happen when create migrator, got %v", err)
+	}
+
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("no error should happen when migrate, got %v", err)
+	}
+
+	if versions := applied(t, m); len(versions) != 2 || !db.Migrator().HasTable(&Order{}) {
+		t.Errorf("all migrations should be applied, got %v", versions)
+	}
+
+	if err := m.RollbackLast(); err != nil {
+		t.Fatalf("no error should happen when rollback, got %v", err)
+	}
+
+	if versions := applied(t, m); len(versions) != 1 || versions[0] != 1 || db.Migrator().HasTable(&Order{}) {
+		t.Errorf("the last migration should be rolled back, got %v", versions)
+	}
+
+	if err := m.MigrateTo(2); err != nil {
+		t.Fatalf("no error should happen when migrate to 2, got %v", err)
+	}
+
+	if err := m.MigrateTo(1); err != nil {
+		t.Fatalf("no error should happen when migrate to 1, got %v", err)
+	}
+
+	if versions := applied(t, m); len(versions) != 1 || versions[0] != 1 {
+		t.Errorf("migrations after 1 should be rolled back, got %v", versions)
+	}
+
+	if err := m.MigrateTo(3); !errors.Is(err, migrations.ErrUnknownVersion) {
+		t.Errorf("expects ErrUnknownVersion, got %v", err)
+	}
+
+	if ups != 3 {
+		t.Errorf("up should be called 3 times, got %v", ups)
+	}
+}
+
+func TestMigrateFailed(t *testing.T) {
+	var ups int32
+	db := openDB(t)
+	failed := &migrations.Migration{
+		Version: 3,
+		Name:    "failed",
+		Up: func(tx *database.DB) error {
+			if err := tx.Create(&Product{Name: "rolled back"}).Error; err != nil {
+				return err
+			}
+			return errors.New("failed")
+		},
+	}
+
+	m, _ := migrations.New(db, nil, append(registered(&ups), failed)...)
+	if err := m.Migrate(); err == nil {
+		t.Fatalf("migrate should fail")
+	}
+
+	if versions := applied(t, m); len(versions) != 2 {
+		t.Errorf("migrations before the failed one should be applied, got %v", versions)
+	}
+
+	var count int64
+	if db.Model(&Product{}).Count(&count); count != 0 {
+		t.Errorf("failed migration should be rolled back, got %v", count)
+	}
+
+	if err := m.RollbackLast(); err != nil {
+		t.Fatalf("no error should happen when rollback, got %v", err)
+	}
+
+	if err := m.MigrateTo(1); err != nil {
+		t.Fatalf("no error should happen when migrate to 1, got %v", err)
+	}
+
+	if err := m.RollbackLast(); err != nil {
+		t.Fatalf("no error should happen when rollback, got %v", err)
+	}
+
+	if err := m.RollbackLast(); !errors.Is(err, migrations.ErrNoAppliedMigrations) {
+		t.Errorf("expects ErrNoAppliedMigrations, got %v", err)
+	}
+}
+
+func TestConcurrentMigrate(t *testing.T) {
+	var (
+		ups  int32
+		db   = openDB(t)
+		wg   sync.WaitGroup
+		errs = make(chan error, 4)
+	)
+
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, _ := migrations.New(db, &migrations.Options{LockRetryInterval: 10 * time.Millisecond}, registered(&ups)...)
+			errs <- m.Migrate()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("no error should happen when migrate, got %v", err)
+		}
+	}
+
+	if ups != 2 {
+		t.Errorf("each migration should be applied once, got %v", ups)
+	}
+}
+
+func TestLockTimeout(t *testing.T) {
+	var ups int32
+	db := openDB(t)
+	locker := &migrations.TableLocker{Table: "schema_migrations_lock"}
+	if locked, err := locker.TryLock(db); err != nil || !locked {
+		t.Fatalf("locker should be locked, got %v, error %v", locked, err)
+	}
+	defer locker.Unlock(db)
+
+	m, _ := migrations.New(db, &migrations.Options{
+		LockTimeout: 50 * time.Millisecond, LockRetryInterval: 10 * time.Millisecond,
+	}, registered(&ups)...)
+
+	if err := m.Migrate(); !errors.Is(err, migrations.ErrLockTimeout) {
+		t.Errorf("expects ErrLockTimeout, got %v", err)
+	}
+}
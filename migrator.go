@@ -2,6 +2,7 @@ package database
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/driver005/database/clause"
 	"github.com/driver005/database/schema"
@@ -9,16 +10,8 @@ import (
 
 // Migrator returns migrator
 func (db *DB) Migrator() Migrator {
-	tx := db.getInstance()
-
 	// apply scopes to migrator
-	for len(tx.Statement.scopes) > 0 {
-		scopes := tx.Statement.scopes
-		tx.Statement.scopes = nil
-		for _, scope := range scopes {
-			tx = scope(tx)
-		}
-	}
+	tx := db.getInstance().executeScopes()
 
 	return tx.Dialector.Migrator(tx.Session(&Session{}))
 }
@@ -28,7 +21,73 @@ func (db *DB) AutoMigrate(dst ...interface{}) error {
 	return db.Migrator().AutoMigrate(dst...)
 }
 
-// ViewOption view option
+// PlanMigration returns pending changes of AutoMigrate for given models without applying them
+func (db *DB) PlanMigration(dst ...interface{}) (*MigrationPlan, error) {
+	return db.Migrator().Plan(dst...)
+}
+
+// MigrationChangeType migration change type
+type MigrationChangeType string
+
+const (
+	CreateTableChange      MigrationChangeType = "create_table"
+	AddColumnChange        MigrationChangeType = "add_column"
+	AlterColumnChange      MigrationChangeType = "alter_column"
+	DropColumnChange       MigrationChangeType = "drop_column"
+	CreateIndexChange      MigrationChangeType = "create_index"
+	DropIndexChange        MigrationChangeType = "drop_index"
+	CreateConstraintChange MigrationChangeType = "create_constraint"
+)
+
+// MigrationChange pending change with the SQL it would run
+type MigrationChange struct {
+	Type        MigrationChangeType
+	Table       string
+	Name        string // column, index or constraint name
+	SQL         []string
+	Destructive bool // could lose data or break queries, e.g. drop column, drop index or narrow column type
+}
+
+// MigrationPlan migration plan generated by Migrator.Plan
+type MigrationPlan struct {
+	Changes []MigrationChange
+}
+
+// HasDestructiveChanges returns true if any change could lose data
+func (plan *MigrationPlan) HasDestructiveChanges() bool {
+	for _, change := range plan.Changes {
+		if change.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+// Script render plan as a SQL script for review
+func (plan *MigrationPlan) Script() string {
+	var script strings.Builder
+	for idx, change := range plan.Changes {
+		if idx > 0 {
+			script.WriteByte('\n')
+		}
+
+		script.WriteString("-- " + string(change.Type) + " " + change.Table)
+		if change.Name != "" {
+			script.WriteString("." + change.Name)
+		}
+		if change.Destructive {
+			script.WriteString(" (DESTRUCTIVE)")
+		}
+		script.WriteByte('\n')
+
+		for _, sql := range change.SQL {
+			script.WriteString(sql + ";\n")
+		}
+	}
+	return script.String()
+}
+
+// ViewOption view option, Query is rendered with inline vars as the view definition
 type ViewOption struct {
 	Replace     bool
 	CheckOption string
@@ -64,6 +123,7 @@ type Index interface {
 type Migrator interface {
 	// AutoMigrate
 	AutoMigrate(dst ...interface{}) error
+	Plan(dst ...interface{}) (*MigrationPlan, error)
 
 	// Database
 	CurrentDatabase() string
@@ -89,6 +149,7 @@ type Migrator interface {
 	// Views
 	CreateView(name string, option ViewOption) error
 	DropView(name string) error
+	HasView(name string) bool
 
 	// Constraints
 	CreateConstraint(dst interface{}, name string) error
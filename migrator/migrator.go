@@ -529,14 +529,65 @@ func (m Migrator) ColumnTypes(value interface{}) ([]database.ColumnType, error)
 	return columnTypes, execErr
 }
 
-// CreateView create view
+// BuildViewQuery build view definition from option's Query with inline vars
+func (m Migrator) BuildViewQuery(option database.ViewOption) (string, error) {
+	if option.Query == nil {
+		return "", database.ErrSubQueryRequired
+	}
+
+	var (
+		sql  strings.Builder
+		stmt = &database.Statement{DB: m.DB, Context: m.DB.Statement.Context}
+	)
+	stmt.AddVar(&sql, option.Query)
+	if option.Query.Error != nil {
+		return "", option.Query.Error
+	}
+
+	return m.Dialector.Explain(sql.String(), stmt.Vars...), nil
+}
+
+// CreateView create view from option's Query
+//
+//	// CREATE VIEW `adult_users` AS SELECT * FROM `users` WHERE age > 20
+//	db.Migrator().CreateView("adult_users", database.ViewOption{Query: db.Model(&User{}).Where("age > ?", 20)})
+//
+//	// CREATE OR REPLACE VIEW `adult_users` AS SELECT * FROM `users` WHERE age > 20 WITH CHECK OPTION
+//	db.Migrator().CreateView("adult_users", database.ViewOption{Query: query, Replace: true, CheckOption: "WITH CHECK OPTION"})
 func (m Migrator) CreateView(name string, option database.ViewOption) error {
-	return database.ErrNotImplemented
+	query, err := m.BuildViewQuery(option)
+	if err != nil {
+		return err
+	}
+
+	createViewSQL := "CREATE "
+	if option.Replace {
+		createViewSQL += "OR REPLACE "
+	}
+	createViewSQL += "VIEW ? AS ?"
+
+	if option.CheckOption != "" {
+		createViewSQL += " " + option.CheckOption
+	}
+
+	return m.DB.Exec(createViewSQL, clause.Table{Name: name}, clause.Expr{SQL: query}).Error
 }
 
 // DropView drop view
 func (m Migrator) DropView(name string) error {
-	return database.ErrNotImplemented
+	return m.DB.Exec("DROP VIEW IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// HasView check has view `name` or not
+func (m Migrator) HasView(name string) bool {
+	var count int64
+	currentDatabase := m.DB.Migrator().CurrentDatabase()
+	m.DB.Raw(
+		"SELECT count(*) FROM information_schema.views WHERE table_schema = ? AND table_name = ?",
+		currentDatabase, name,
+	).Row().Scan(&count)
+
+	return count > 0
 }
 
 func buildConstraint(constraint *schema.Constraint) (sql string, results []interface{}) {
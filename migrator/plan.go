@@ -0,0 +1,247 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/driver005/database"
+)
+
+// planConnPool runs queries to inspect the database but records statements passed to ExecContext instead of
+// executing them
+type planConnPool struct {
+	database.ConnPool
+	dialector  database.Dialector
+	statements []string
+}
+
+func (pool *planConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	pool.statements = append(pool.statements, pool.dialector.Explain(query, args...))
+	return driver.RowsAffected(0), nil
+}
+
+// Plan returns changes AutoMigrate would apply for values, plus columns and indexes that exist in database but not
+// in models, with the SQL of each change. Nothing is executed.
+func (m Migrator) Plan(values ...interface{}) (*database.MigrationPlan, error) {
+	var (
+		plan = &database.MigrationPlan{}
+		pool = &planConnPool{ConnPool: m.DB.Statement.ConnPool, dialector: m.Dialector}
+		tx   = m.DB.Session(&database.Session{Context: m.DB.Statement.Context})
+	)
+	tx.DryRun = false
+	tx.Statement.ConnPool = pool
+	planner := tx.Migrator()
+
+	record := func(change database.MigrationChange, fc func() error) error {
+		pool.statements = nil
+		if err := fc(); err != nil {
+			return err
+		}
+
+		if len(pool.statements) > 0 {
+			change.SQL = pool.statements
+			plan.Changes = append(plan.Changes, change)
+		}
+		return nil
+	}
+
+	for _, value := range m.ReorderModels(values, true) {
+		if err := m.RunWithValue(value, func(stmt *database.Statement) error {
+			if !planner.HasTable(value) {
+				return record(database.MigrationChange{Type: database.CreateTableChange, Table: stmt.Table}, func() error {
+					return planner.CreateTable(value)
+				})
+			}
+
+			columnTypes, err := planner.ColumnTypes(value)
+			if err != nil {
+				return err
+			}
+
+			for _, dbName := range stmt.Schema.DBNames {
+				field := stmt.Schema.FieldsByDBName[dbName]
+				var foundColumn database.ColumnType
+
+				for _, columnType := range columnTypes {
+					if columnType.Name() == dbName {
+						foundColumn = columnType
+						break
+					}
+				}
+
+				if foundColumn == nil {
+					err = record(database.MigrationChange{Type: database.AddColumnChange, Table: stmt.Table, Name: dbName}, func() error {
+						return planner.AddColumn(value, dbName)
+					})
+				} else {
+					err = record(database.MigrationChange{
+						Type: database.AlterColumnChange, Table: stmt.Table, Name: dbName,
+						Destructive: !field.PrimaryKey && m.columnTypeDestructive(planner, planner.FullDataTypeOf(field).SQL, foundColumn),
+					}, func() error {
+						return planner.MigrateColumn(value, field, foundColumn)
+					})
+				}
+
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, columnType := range columnTypes {
+				if _, ok := stmt.Schema.FieldsByDBName[columnType.Name()]; !ok {
+					name := columnType.Name()
+					if err := record(database.MigrationChange{Type: database.DropColumnChange, Table: stmt.Table, Name: name, Destructive: true}, func() error {
+						return planner.DropColumn(value, name)
+					}); err != nil {
+						return err
+					}
+				}
+			}
+
+			for _, rel := range stmt.Schema.Relationships.Relations {
+				if !m.DB.Config.DisableForeignKeyConstraintWhenMigrating {
+					if constraint := rel.ParseConstraint(); constraint != nil &&
+						constraint.Schema == stmt.Schema && !planner.HasConstraint(value, constraint.Name) {
+						if err := record(database.MigrationChange{Type: database.CreateConstraintChange, Table: stmt.Table, Name: constraint.Name}, func() error {
+							return planner.CreateConstraint(value, constraint.Name)
+						}); err != nil {
+							return err
+						}
+					}
+				}
+			}
+
+			for _, chk := range stmt.Schema.ParseCheckConstraints() {
+				if !planner.HasConstraint(value, chk.Name) {
+					name := chk.Name
+					if err := record(database.MigrationChange{Type: database.CreateConstraintChange, Table: stmt.Table, Name: name}, func() error {
+						return planner.CreateConstraint(value, name)
+					}); err != nil {
+						return err
+					}
+				}
+			}
+
+			indexes := stmt.Schema.ParseIndexes()
+			indexNames := make([]string, 0, len(indexes))
+			for name := range indexes {
+				indexNames = append(indexNames, name)
+			}
+			sort.Strings(indexNames)
+
+			for _, name := range indexNames {
+				if name := name; !planner.HasIndex(value, name) {
+					if err := record(database.MigrationChange{Type: database.CreateIndexChange, Table: stmt.Table, Name: name}, func() error {
+						return planner.CreateIndex(value, name)
+					}); err != nil {
+						return err
+					}
+				}
+			}
+
+			// unique indexes are skipped as they usually back unique constraints of columns, so are indexes created
+			// for foreign keys
+			if existingIndexes, err := planner.GetIndexes(value); err == nil {
+				sort.Slice(existingIndexes, func(i, j int) bool {
+					return existingIndexes[i].Name() < existingIndexes[j].Name()
+				})
+
+				constraints := map[string]bool{}
+				for _, rel := range stmt.Schema.Relationships.Relations {
+					if constraint := rel.ParseConstraint(); constraint != nil {
+						constraints[constraint.Name] = true
+					}
+				}
+
+				for _, idx := range existingIndexes {
+					primary, _ := idx.PrimaryKey()
+					unique, _ := idx.Unique()
+					if _, ok := indexes[idx.Name()]; !ok && !primary && !unique && !constraints[idx.Name()] {
+						name := idx.Name()
+						if err := record(database.MigrationChange{Type: database.DropIndexChange, Table: stmt.Table, Name: name, Destructive: true}, func() error {
+							return planner.DropIndex(value, name)
+						}); err != nil {
+							return err
+						}
+					}
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+var (
+	// column options following data types, e.g: varchar(64) NOT NULL DEFAULT 'x'
+	columnOptionsRegexp = regexp.MustCompile(`\s+(not null|null|default|primary key|unique|auto_increment|autoincrement|identity|generated|check|collate|comment|references)\b.*$`)
+	dataTypeRegexp      = regexp.MustCompile(`^([^(]+)(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?(.*)$`)
+)
+
+// dataType normalized data type, e.g: decimal(10,2) is decimal of size 10 and scale 2
+type dataType struct {
+	name        string
+	size, scale int64
+}
+
+func parseDataType(fullDataType string) (result dataType) {
+	fullDataType = columnOptionsRegexp.ReplaceAllString(strings.TrimSpace(strings.ToLower(fullDataType)), "")
+	if matches := dataTypeRegexp.FindStringSubmatch(fullDataType); len(matches) == 5 {
+		result.name = strings.Join(strings.Fields(matches[1]+" "+matches[4]), " ")
+		result.size, _ = strconv.ParseInt(matches[2], 10, 64)
+		result.scale, _ = strconv.ParseInt(matches[3], 10, 64)
+	}
+	return
+}
+
+// columnTypeDestructive check field's full data type could lose data of the column, the data type differs or its
+// size, precision or scale is narrowed, widened sizes keep data
+func (m Migrator) columnTypeDestructive(migrator database.Migrator, fullDataType string, columnType database.ColumnType) bool {
+	expected := parseDataType(fullDataType)
+	current := parseDataType(columnType.DatabaseTypeName())
+	if fullColumnType, ok := columnType.ColumnType(); ok {
+		if typ := parseDataType(fullColumnType); typ.name != "" {
+			current = typ
+		}
+	}
+
+	if expected.name != current.name && !strings.HasPrefix(expected.name, current.name+" ") {
+		var aliased bool
+		for _, alias := range migrator.GetTypeAliases(current.name) {
+			if expected.name == alias {
+				aliased = true
+				break
+			}
+		}
+
+		if !aliased {
+			return true
+		}
+	}
+
+	if expected.size > 0 {
+		if precision, scale, ok := columnType.DecimalSize(); ok && precision > 0 {
+			// digits of the integer part and the fractional part are both kept
+			return expected.size-expected.scale < precision-scale || expected.scale < scale
+		}
+
+		if length, ok := columnType.Length(); ok && length > 0 && length < math.MaxInt32 {
+			return expected.size < length
+		}
+
+		if current.size > 0 {
+			return expected.size-expected.scale < current.size-current.scale || expected.scale < current.scale
+		}
+	}
+	return false
+}
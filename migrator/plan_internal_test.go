@@ -0,0 +1,46 @@
+package migrator
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestColumnTypeDestructive(t *testing.T) {
+	column := func(dataType, columnType string, length, precision, scale int64) ColumnType {
+		return ColumnType{
+			DataTypeValue:    sql.NullString{String: dataType, Valid: true},
+			ColumnTypeValue:  sql.NullString{String: columnType, Valid: columnType != ""},
+			LengthValue:      sql.NullInt64{Int64: length, Valid: true},
+			DecimalSizeValue: sql.NullInt64{Int64: precision, Valid: true},
+			ScaleValue:       sql.NullInt64{Int64: scale, Valid: true},
+		}
+	}
+
+	results := []struct {
+		Name         string
+		FullDataType string
+		Column       ColumnType
+		Destructive  bool
+	}{
+		{"SameType", "varchar(100) NOT NULL", column("VARCHAR", "varchar(100)", 100, 0, 0), false},
+		{"WidenSize", "varchar(255)", column("VARCHAR", "varchar(100)", 100, 0, 0), false},
+		{"NarrowSize", "varchar(50) DEFAULT 'x'", column("VARCHAR", "varchar(100)", 100, 0, 0), true},
+		{"NarrowSizeOfColumnType", "varchar(50)", column("VARCHAR", "varchar(100)", 0, 0, 0), true},
+		{"ChangeType", "bigint", column("VARCHAR", "varchar(100)", 100, 0, 0), true},
+		{"PrefixedType", "int", column("INTEGER", "", 0, 0, 0), true},
+		{"MultiWordType", "timestamp(3) with time zone", column("TIMESTAMP", "timestamp(3) with time zone", 0, 0, 0), false},
+		{"WidenDecimal", "decimal(12,2)", column("DECIMAL", "decimal(10,2)", 0, 10, 2), false},
+		{"NarrowDecimalPrecision", "decimal(8,2)", column("DECIMAL", "decimal(10,2)", 0, 10, 2), true},
+		{"NarrowDecimalScale", "decimal(10,1)", column("DECIMAL", "decimal(10,2)", 0, 10, 2), true},
+		{"IncreaseDecimalScale", "decimal(10,4)", column("DECIMAL", "decimal(10,2)", 0, 10, 2), true},
+		{"Unsigned", "bigint unsigned AUTO_INCREMENT", column("BIGINT", "bigint unsigned", 0, 0, 0), false},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			if destructive := (Migrator{}).columnTypeDestructive(Migrator{}, result.FullDataType, result.Column); destructive != result.Destructive {
+				t.Errorf("destructive expects %v got %v", result.Destructive, destructive)
+			}
+		})
+	}
+}
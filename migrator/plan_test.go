@@ -0,0 +1,105 @@
+package migrator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/logger"
+)
+
+type User struct {
+	ID   uint
+	Name string `database:"size:64;index"`
+	Age  int
+	Nick string
+}
+
+type UserV2 struct {
+	ID    uint
+	Name  string `database:"size:64"`
+	Email string `database:"index"`
+}
+
+func (UserV2) TableName() string {
+	return "users"
+}
+
+func openDB(t *testing.T) *database.DB {
+	dsn := filepath.Join(t.TempDir(), "plan.db") + "?_pragma=foreign_keys(1)"
+	db, err := database.Open(sqlite.Open(dsn), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestPlan(t *testing.T) {
+	db := openDB(t)
+
+	plan, err := db.PlanMigration(&User{})
+	if err != nil {
+		t.Fatalf("no error should happen when plan, got %v", err)
+	}
+
+	if len(plan.Changes) != 1 || plan.Changes[0].Type != database.CreateTableChange || plan.HasDestructiveChanges() {
+		t.Fatalf("table should be created, got %+v", plan.Changes)
+	}
+
+	if db.Migrator().HasTable(&User{}) {
+		t.Fatalf("planning shouldn't create tables")
+	}
+
+	if err := db.AutoMigrate(&User{}); err != nil {
+		t.Fatalf("no error should happen when migrate, got %v", err)
+	}
+
+	if plan, err = db.PlanMigration(&User{}); err != nil || len(plan.Changes) != 0 {
+		t.Fatalf("migrated tables shouldn't have changes, got %+v, error %v", plan.Changes, err)
+	}
+
+	if plan, err = db.PlanMigration(&UserV2{}); err != nil {
+		t.Fatalf("no error should happen when plan, got %v", err)
+	}
+
+	expects := map[database.MigrationChangeType]map[string]bool{
+		database.AddColumnChange:   {"email": false},
+		database.DropColumnChange:  {"age": true, "nick": true},
+		database.CreateIndexChange: {"idx_users_email": false},
+		database.DropIndexChange:   {"idx_users_name": true},
+	}
+
+	for _, change := range plan.Changes {
+		destructive, ok := expects[change.Type][change.Name]
+		if !ok {
+			t.Errorf("unexpected change %+v", change)
+			continue
+		}
+		delete(expects[change.Type], change.Name)
+
+		if change.Destructive != destructive {
+			t.Errorf("change %v %v destructive expects %v got %v", change.Type, change.Name, destructive, change.Destructive)
+		}
+
+		if len(change.SQL) == 0 {
+			t.Errorf("change %v %v should have SQL", change.Type, change.Name)
+		}
+	}
+
+	for typ, names := range expects {
+		for name := range names {
+			t.Errorf("change %v %v expected", typ, name)
+		}
+	}
+
+	if !db.Migrator().HasColumn(&User{}, "age") || db.Migrator().HasColumn(&UserV2{}, "email") {
+		t.Errorf("planning shouldn't change tables")
+	}
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/schema"
+)
+
+// Version optimistic lock version, new records start with version 1. Updating a record with a valid version only
+// succeeds if the version in database is unchanged, and increases it, otherwise the update reports ErrStaleObject and
+// the record should be reloaded. Upserts, e.g. saving slices or associations, increase the version in database
+// without checking it, the versions of their records are left unchanged
+//
+//	type Product struct {
+//		ID      uint
+//		Price   int
+//		Version database.Version
+//	}
+type Version sql.NullInt64
+
+// Scan implements the Scanner interface.
+func (v *Version) Scan(value interface{}) error {
+	return (*sql.NullInt64)(v).Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (v Version) Value() (driver.Value, error) {
+	if !v.Valid {
+		return nil, nil
+	}
+	return v.Int64, nil
+}
+
+func (v Version) MarshalJSON() ([]byte, error) {
+	if v.Valid {
+		return json.Marshal(v.Int64)
+	}
+	return json.Marshal(nil)
+}
+
+func (v *Version) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		v.Valid = false
+		return nil
+	}
+	err := json.Unmarshal(b, &v.Int64)
+	if err == nil {
+		v.Valid = true
+	}
+	return err
+}
+
+func (Version) CreateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{VersionCreateClause{Field: f}}
+}
+
+type VersionCreateClause struct {
+	Field *schema.Field
+}
+
+func (v VersionCreateClause) Name() string {
+	return ""
+}
+
+func (v VersionCreateClause) Build(clause.Builder) {
+}
+
+func (v VersionCreateClause) MergeClause(*clause.Clause) {
+}
+
+func (v VersionCreateClause) ModifyStatement(stmt *Statement) {
+	initVersion := func(reflectValue reflect.Value) {
+		if _, zero := v.Field.ValueOf(stmt.Context, reflectValue); zero {
+			stmt.AddError(v.Field.Set(stmt.Context, reflectValue, Version{Int64: 1, Valid: true}))
+		}
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			initVersion(reflect.Indirect(stmt.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		if stmt.ReflectValue.CanAddr() {
+			initVersion(stmt.ReflectValue)
+		}
+	}
+}
+
+func (Version) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{VersionUpdateClause{Field: f}}
+}
+
+type VersionUpdateClause struct {
+	Field *schema.Field
+}
+
+func (v VersionUpdateClause) Name() string {
+	return ""
+}
+
+func (v VersionUpdateClause) Build(clause.Builder) {
+}
+
+func (v VersionUpdateClause) MergeClause(*clause.Clause) {
+}
+
+// ModifyStatement checks the version of the updating record and assigns the next version, the update callback sets
+// it to the record once the update succeeded, or reports ErrStaleObject if no rows are affected
+func (v VersionUpdateClause) ModifyStatement(stmt *Statement) {
+	if _, ok := stmt.Clauses["optimistic_lock_enabled"]; ok || stmt.SQL.Len() > 0 || stmt.ReflectValue.Kind() != reflect.Struct {
+		return
+	}
+
+	value, zero := v.Field.ValueOf(stmt.Context, stmt.ReflectValue)
+	if version, ok := value.(Version); !zero && ok && version.Valid {
+		groupOrConditions(stmt)
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: v.Field.DBName}, Value: version.Int64},
+		}})
+
+		// the next version is assigned by the update callback instead of the value of the record
+		stmt.Omits = append(stmt.Omits, v.Field.DBName)
+		stmt.Clauses["optimistic_lock_enabled"] = clause.Clause{Expression: clause.Set{
+			{Column: clause.Column{Name: v.Field.DBName}, Value: Version{Int64: version.Int64 + 1, Valid: true}},
+		}}
+	}
+}
@@ -0,0 +1,128 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/driver005/database"
+)
+
+type Shop struct {
+	ID       uint
+	Name     string
+	Products []Product
+}
+
+type Product struct {
+	ID      uint
+	ShopID  *uint
+	Price   int
+	Version database.Version
+}
+
+func versionOf(t *testing.T, db *database.DB, id uint) int64 {
+	var product Product
+	if err := db.First(&product, id).Error; err != nil {
+		t.Fatalf("no error should happen when find product, got %v", err)
+	}
+	return product.Version.Int64
+}
+
+func TestVersion(t *testing.T) {
+	db := openDB(t, &Shop{}, &Product{})
+
+	product := Product{Price: 10}
+	if err := db.Create(&product).Error; err != nil || product.Version.Int64 != 1 {
+		t.Fatalf("new product should start with version 1, got %+v, error %v", product, err)
+	}
+
+	stale := product
+	if err := db.Model(&product).Update("price", 20).Error; err != nil {
+		t.Fatalf("no error should happen when update, got %v", err)
+	}
+
+	if product.Version.Int64 != 2 || versionOf(t, db, product.ID) != 2 {
+		t.Errorf("version should be increased to 2, got %v", product.Version.Int64)
+	}
+
+	if err := db.Model(&product).Updates(map[string]interface{}{"price": 30}).Error; err != nil || product.Version.Int64 != 3 {
+		t.Errorf("version should be increased by map updates, got %v, error %v", product.Version.Int64, err)
+	}
+
+	if err := db.Model(&stale).Update("price", 40).Error; !errors.Is(err, database.ErrStaleObject) {
+		t.Fatalf("stale product shouldn't be updated, got %v", err)
+	}
+
+	if stale.Version.Int64 != 1 {
+		t.Errorf("version of stale product shouldn't be changed, got %v", stale.Version.Int64)
+	}
+
+	if err := db.Model(&stale).Updates(Product{Price: 40}).Error; !errors.Is(err, database.ErrStaleObject) || stale.Version.Int64 != 1 {
+		t.Errorf("stale product shouldn't be updated, got version %v, error %v", stale.Version.Int64, err)
+	}
+}
+
+func TestVersionSave(t *testing.T) {
+	db := openDB(t, &Shop{}, &Product{})
+
+	product := Product{Price: 10}
+	db.Create(&product)
+	stale := product
+
+	product.Price = 20
+	if err := db.Save(&product).Error; err != nil || product.Version.Int64 != 2 {
+		t.Fatalf("version should be increased by save, got %v, error %v", product.Version.Int64, err)
+	}
+
+	stale.Price = 30
+	if err := db.Save(&stale).Error; !errors.Is(err, database.ErrStaleObject) || stale.Version.Int64 != 1 {
+		t.Errorf("stale product shouldn't be saved, got version %v, error %v", stale.Version.Int64, err)
+	}
+
+	var count int64
+	if db.Model(&Product{}).Count(&count); count != 1 {
+		t.Errorf("stale product shouldn't be created, got %v products", count)
+	}
+
+	// upserts increase versions in database
+	products := []Product{product, {Price: 50}}
+	if err := db.Save(&products).Error; err != nil {
+		t.Fatalf("no error should happen when save products, got %v", err)
+	}
+
+	if version := versionOf(t, db, product.ID); version != 3 {
+		t.Errorf("version of upserted product should be 3, got %v", version)
+	}
+
+	if version := versionOf(t, db, products[1].ID); version != 1 {
+		t.Errorf("version of created product should be 1, got %v", version)
+	}
+}
+
+func TestVersionAssociations(t *testing.T) {
+	db := openDB(t, &Shop{}, &Product{})
+
+	shop := Shop{Name: "shop", Products: []Product{{Price: 10}}}
+	if err := db.Create(&shop).Error; err != nil {
+		t.Fatalf("no error should happen when create shop, got %v", err)
+	}
+
+	if shop.Products[0].Version.Int64 != 1 {
+		t.Errorf("associated product should start with version 1, got %v", shop.Products[0].Version.Int64)
+	}
+
+	product := shop.Products[0]
+	if err := db.Model(&product).Update("price", 20).Error; err != nil {
+		t.Fatalf("no error should happen when update product, got %v", err)
+	}
+
+	shop.Products[0].Price = 30
+	if err := db.Session(&database.Session{FullSaveAssociations: true}).Save(&shop).Error; err != nil {
+		t.Fatalf("no error should happen when save shop, got %v", err)
+	}
+
+	// the stale version of the association doesn't overwrite the version in database
+	if version := versionOf(t, db, product.ID); version != 3 {
+		t.Errorf("version of upserted product should be 3, got %v", version)
+	}
+}
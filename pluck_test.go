@@ -0,0 +1,110 @@
+package database_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/driver005/database"
+)
+
+type Contact struct {
+	ID       uint
+	Name     string
+	Nickname *string
+	Tags     []string `database:"serializer:json"`
+	Score    int
+}
+
+func TestPluckMany(t *testing.T) {
+	db := openDB(t, &Contact{})
+	nickname := "jin"
+	contacts := []Contact{
+		{Name: "jinzhu", Nickname: &nickname, Tags: []string{"admin"}, Score: 10},
+		{Name: "tom", Score: 20},
+	}
+	if err := db.Create(&contacts).Error; err != nil {
+		t.Fatalf("no error should happen when create contacts, got %v", err)
+	}
+
+	var (
+		ids       []uint
+		names     []string
+		nicknames []string
+		tags      [][]string
+	)
+	result := db.Model(&Contact{}).Order("id").PluckMany([]string{"ID", "name", "nickname", "Tags"}, &ids, &names, &nicknames, &tags)
+	if result.Error != nil || result.RowsAffected != 2 {
+		t.Fatalf("no error should happen when pluck many, got %v rows, error %v", result.RowsAffected, result.Error)
+	}
+
+	if fmt.Sprint(ids, names, nicknames, tags) != fmt.Sprint([]uint{1, 2}, []string{"jinzhu", "tom"}, []string{"jin", ""}, [][]string{{"admin"}, nil}) {
+		t.Errorf("columns should be plucked with NULL as zero values and serializers applied, got %v %v %q %v", ids, names, nicknames, tags)
+	}
+
+	var (
+		upperNames []string
+		scores     []*int64
+	)
+	if err := db.Table("contacts").Where("score > ?", 10).PluckMany([]string{"upper(name)", "score"}, &upperNames, &scores).Error; err != nil {
+		t.Fatalf("no error should happen when pluck expressions, got %v", err)
+	}
+
+	if len(upperNames) != 1 || upperNames[0] != "TOM" || len(scores) != 1 || *scores[0] != 20 {
+		t.Errorf("expressions should be plucked, got %v %v", upperNames, scores)
+	}
+}
+
+func TestPluckMap(t *testing.T) {
+	db := openDB(t, &Contact{})
+	contacts := []Contact{{Name: "jinzhu", Tags: []string{"admin", "user"}, Score: 10}, {Name: "tom", Score: 20}}
+	if err := db.Create(&contacts).Error; err != nil {
+		t.Fatalf("no error should happen when create contacts, got %v", err)
+	}
+
+	var names map[uint]string
+	if result := db.Model(&Contact{}).PluckMap("id", "name", &names); result.Error != nil || result.RowsAffected != 2 {
+		t.Fatalf("no error should happen when pluck map, got %v rows, error %v", result.RowsAffected, result.Error)
+	}
+
+	if len(names) != 2 || names[1] != "jinzhu" || names[2] != "tom" {
+		t.Errorf("names should be plucked by id, got %v", names)
+	}
+
+	tags := map[string][]string{}
+	db.Model(&Contact{}).PluckMap("Name", "Tags", &tags)
+	if len(tags) != 2 || fmt.Sprint(tags["jinzhu"]) != "[admin user]" || tags["tom"] != nil {
+		t.Errorf("serialized values should be plucked, got %v", tags)
+	}
+
+	scores := map[string]*int{}
+	db.Model(&Contact{}).Where("score > ?", 10).PluckMap("name", "score", &scores)
+	if len(scores) != 1 || *scores["tom"] != 20 {
+		t.Errorf("values should be plucked into pointers, got %v", scores)
+	}
+}
+
+func TestPluckErrors(t *testing.T) {
+	db := openDB(t, &Contact{})
+	db.Create(&Contact{Name: "jinzhu"})
+
+	var (
+		ids   []uint
+		names []string
+	)
+	results := []struct {
+		Name string
+		Err  error
+	}{
+		{"Count", db.Model(&Contact{}).PluckMany([]string{"id"}, &ids, &names).Error},
+		{"NotSlice", db.Model(&Contact{}).PluckMany([]string{"id"}, ids).Error},
+		{"NotMap", db.Model(&Contact{}).PluckMap("id", "name", &names).Error},
+		{"Convert", db.Model(&Contact{}).PluckMany([]string{"id"}, &names).Error},
+	}
+
+	for _, result := range results {
+		if !errors.Is(result.Err, database.ErrInvalidData) {
+			t.Errorf("%v: error should be ErrInvalidData, got %v", result.Name, result.Err)
+		}
+	}
+}
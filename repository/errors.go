@@ -1,10 +1,18 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/driver005/database"
 )
 
 var (
 	// ErrNotFound is a convenience reference for the actual GORM error
 	ErrNotFound = database.ErrRecordNotFound
+	// ErrInvalidID ID doesn't match the primary key of the model
+	ErrInvalidID = errors.New("invalid ID")
+	// ErrInvalidCursor cursor is malformed or was created with other sort keys
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidFilter filter references unknown fields or operators, or has invalid values
+	ErrInvalidFilter = errors.New("invalid filter")
 )
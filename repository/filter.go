@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/schema"
+)
+
+// Operator filter operator
+type Operator string
+
+const (
+	OpEq      Operator = "eq"
+	OpNeq     Operator = "neq"
+	OpGt      Operator = "gt"
+	OpGte     Operator = "gte"
+	OpLt      Operator = "lt"
+	OpLte     Operator = "lte"
+	OpIn      Operator = "in"
+	OpNotIn   Operator = "nin"
+	OpLike    Operator = "like"
+	OpBetween Operator = "between"
+	// OpIsNull matches NULL values when Value is true, and non NULL values when it's false
+	OpIsNull Operator = "null"
+)
+
+// Filter condition on Field, a field name or column name of the model. Filter without Field is a group combining
+// And and Or filters. Only fields tagged filterable can be filtered, and only fields tagged sortable can be sorted
+//
+//	type User struct {
+//		ID   uint   `database:"sortable"`
+//		Name string `database:"filterable;sortable"`
+//		Age  int    `database:"filterable"`
+//	}
+//
+//	repository.Filter{Or: []repository.Filter{
+//		{Field: "Age", Operator: repository.OpGt, Value: 30},
+//		{Field: "name", Operator: repository.OpLike, Value: "jin%"},
+//	}}
+type Filter struct {
+	Field    string
+	Operator Operator
+	Value    interface{}
+	And      []Filter
+	Or       []Filter
+}
+
+// Query filters combined with AND and sort keys
+type Query struct {
+	Filters []Filter
+	Sorts   []Sort
+}
+
+// Build compiles q into conditions and orders after validating fields with s
+func (q Query) Build(s *schema.Schema) ([]clause.Expression, []clause.OrderByColumn, error) {
+	exprs := make([]clause.Expression, 0, len(q.Filters))
+	for _, filter := range q.Filters {
+		expr, err := filter.Build(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	orders := make([]clause.OrderByColumn, 0, len(q.Sorts))
+	for _, sort := range q.Sorts {
+		field, err := lookUpFilterField(s, sort.Column, "SORTABLE")
+		if err != nil {
+			return nil, nil, err
+		}
+		orders = append(orders, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Desc: sort.Desc,
+		})
+	}
+	return exprs, orders, nil
+}
+
+// Scope returns a scope applying q to statements, fields are validated with the schema of model
+func (q Query) Scope(model interface{}) func(*database.DB) *database.DB {
+	return func(db *database.DB) *database.DB {
+		stmt := &database.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			db.AddError(err)
+			return db
+		}
+
+		exprs, orders, err := q.Build(stmt.Schema)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+
+		if len(exprs) > 0 {
+			db = db.Clauses(clause.Where{Exprs: exprs})
+		}
+		for _, order := range orders {
+			db = db.Order(order)
+		}
+		return db
+	}
+}
+
+// Filter returns a scope applying q to statements on T
+func (r *GenericRepository[T, ID]) Filter(q Query) func(*database.DB) *database.DB {
+	return q.Scope(new(T))
+}
+
+// Build compiles f into an expression after validating fields with s
+func (f Filter) Build(s *schema.Schema) (clause.Expression, error) {
+	if f.Field == "" {
+		return f.buildGroup(s)
+	}
+
+	field, err := lookUpFilterField(s, f.Field, "FILTERABLE")
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Operator == OpLike && field.DataType != schema.String {
+		return nil, fmt.Errorf("%w: like isn't supported by %s", ErrInvalidFilter, f.Field)
+	}
+
+	column := clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+	switch f.Operator {
+	case OpEq, "":
+		value, err := filterValue(field, f.Value)
+		return clause.Eq{Column: column, Value: value}, err
+	case OpNeq:
+		value, err := filterValue(field, f.Value)
+		return clause.Neq{Column: column, Value: value}, err
+	case OpGt:
+		value, err := filterValue(field, f.Value)
+		return clause.Gt{Column: column, Value: value}, err
+	case OpGte:
+		value, err := filterValue(field, f.Value)
+		return clause.Gte{Column: column, Value: value}, err
+	case OpLt:
+		value, err := filterValue(field, f.Value)
+		return clause.Lt{Column: column, Value: value}, err
+	case OpLte:
+		value, err := filterValue(field, f.Value)
+		return clause.Lte{Column: column, Value: value}, err
+	case OpLike:
+		if _, ok := f.Value.(string); !ok {
+			return nil, fmt.Errorf("%w: %s like requires a string", ErrInvalidFilter, f.Field)
+		}
+		return clause.Like{Column: column, Value: f.Value}, nil
+	case OpIn, OpNotIn:
+		values, err := filterValues(field, f.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Operator == OpNotIn {
+			return clause.Not(clause.IN{Column: column, Values: values}), nil
+		}
+		return clause.IN{Column: column, Values: values}, nil
+	case OpBetween:
+		values, err := filterValues(field, f.Value)
+		if err != nil {
+			return nil, err
+		} else if len(values) != 2 {
+			return nil, fmt.Errorf("%w: %s between requires two values", ErrInvalidFilter, f.Field)
+		}
+		return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{column, values[0], values[1]}}, nil
+	case OpIsNull:
+		isNull, err := strconv.ParseBool(fmt.Sprint(f.Value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s null requires a boolean", ErrInvalidFilter, f.Field)
+		}
+
+		if isNull {
+			return clause.Eq{Column: column, Value: nil}, nil
+		}
+		return clause.Neq{Column: column, Value: nil}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %s", ErrInvalidFilter, f.Operator)
+	}
+}
+
+func (f Filter) buildGroup(s *schema.Schema) (clause.Expression, error) {
+	if len(f.And) == 0 && len(f.Or) == 0 {
+		return nil, fmt.Errorf("%w: filter without field", ErrInvalidFilter)
+	}
+
+	ands := make([]clause.Expression, 0, len(f.And)+1)
+	for _, filter := range f.And {
+		expr, err := filter.Build(s)
+		if err != nil {
+			return nil, err
+		}
+		ands = append(ands, expr)
+	}
+
+	if len(f.Or) > 0 {
+		ors := make([]clause.Expression, 0, len(f.Or))
+		for _, filter := range f.Or {
+			expr, err := filter.Build(s)
+			if err != nil {
+				return nil, err
+			}
+			ors = append(ors, expr)
+		}
+		ands = append(ands, clause.Or(ors...))
+	}
+	return clause.And(ands...), nil
+}
+
+// lookUpFilterField returns the field named name, fields without the tag setting are denied
+func lookUpFilterField(s *schema.Schema, name string, setting string) (*schema.Field, error) {
+	field, ok := s.FieldsByName[name]
+	if !ok {
+		field, ok = s.FieldsByDBName[name]
+	}
+
+	if !ok || field.DBName == "" {
+		return nil, fmt.Errorf("%w: unknown field %s", ErrInvalidFilter, name)
+	}
+
+	if _, ok := field.TagSettings[setting]; !ok {
+		return nil, fmt.Errorf("%w: field %s isn't %s", ErrInvalidFilter, name, strings.ToLower(setting))
+	}
+	return field, nil
+}
+
+// filterValue converts strings, e.g. parsed from URL queries, into field's data type
+func filterValue(field *schema.Field, value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	var err error
+	switch field.DataType {
+	case schema.Bool:
+		value, err = strconv.ParseBool(str)
+	case schema.Int:
+		value, err = strconv.ParseInt(str, 10, 64)
+	case schema.Uint:
+		value, err = strconv.ParseUint(str, 10, 64)
+	case schema.Float:
+		value, err = strconv.ParseFloat(str, 64)
+	case schema.Time:
+		value, err = time.Parse(time.RFC3339, str)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid value %q for %s", ErrInvalidFilter, str, field.Name)
+	}
+	return value, nil
+}
+
+// filterValues converts value, a slice or a comma separated string, into values of field's data type
+func filterValues(field *schema.Field, value interface{}) ([]interface{}, error) {
+	var values []interface{}
+	if str, ok := value.(string); ok {
+		for _, v := range strings.Split(str, ",") {
+			values = append(values, v)
+		}
+	} else if reflectValue := reflect.ValueOf(value); reflectValue.Kind() == reflect.Slice || reflectValue.Kind() == reflect.Array {
+		for i := 0; i < reflectValue.Len(); i++ {
+			values = append(values, reflectValue.Index(i).Interface())
+		}
+	} else {
+		values = append(values, value)
+	}
+
+	for i, v := range values {
+		var err error
+		if values[i], err = filterValue(field, v); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+var filterKeyRegexp = regexp.MustCompile(`^filter((?:\[[^\[\]]+\])+)$`)
+
+// ParseQuery parses filters and sorts from URL query values, fields are validated when the query is built
+//
+//	?filter[age][gt]=30&filter[name]=jinzhu&filter[or][role][in]=admin,owner&filter[or][vip][eq]=true&sort=-created_at,name
+//
+// filter[field]=value is an eq filter, filters under filter[or] are combined with OR, values of in, nin and between
+// are comma separated, sort keys prefixed with - are descending
+func ParseQuery(values url.Values) (Query, error) {
+	var (
+		query Query
+		or    []Filter
+		keys  = make([]string, 0, len(values))
+	)
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "sort" {
+			for _, value := range values[key] {
+				for _, column := range strings.Split(value, ",") {
+					if column = strings.TrimSpace(column); column == "" {
+						continue
+					}
+
+					if strings.HasPrefix(column, "-") {
+						query.Sorts = append(query.Sorts, Sort{Column: column[1:], Desc: true})
+					} else {
+						query.Sorts = append(query.Sorts, Sort{Column: strings.TrimPrefix(column, "+")})
+					}
+				}
+			}
+			continue
+		}
+
+		matches := filterKeyRegexp.FindStringSubmatch(key)
+		if matches == nil {
+			continue
+		}
+
+		parts := strings.Split(strings.Trim(matches[1], "[]"), "][")
+		isOr := parts[0] == "or"
+		if isOr {
+			parts = parts[1:]
+		}
+
+		var filter Filter
+		switch len(parts) {
+		case 1:
+			filter = Filter{Field: parts[0], Operator: OpEq}
+		case 2:
+			filter = Filter{Field: parts[0], Operator: Operator(parts[1])}
+		default:
+			return query, fmt.Errorf("%w: %s", ErrInvalidFilter, key)
+		}
+
+		for _, value := range values[key] {
+			filter.Value = value
+			if isOr {
+				or = append(or, filter)
+			} else {
+				query.Filters = append(query.Filters, filter)
+			}
+		}
+	}
+
+	if len(or) > 0 {
+		query.Filters = append(query.Filters, Filter{Or: or})
+	}
+	return query, nil
+}
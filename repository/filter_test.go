@@ -0,0 +1,132 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/repository"
+)
+
+type Post struct {
+	ID          uint   `database:"sortable"`
+	Title       string `database:"filterable;sortable"`
+	Score       int    `database:"filterable;sortable"`
+	Password    string
+	PublishedAt *time.Time `database:"filterable"`
+}
+
+func TestParseQuery(t *testing.T) {
+	values, _ := url.ParseQuery("filter[score][gt]=30&filter[title]=jinzhu&filter[or][ID][in]=1,2&filter[or][published_at][null]=true&sort=-score,title&page=2")
+	query, err := repository.ParseQuery(values)
+	if err != nil {
+		t.Fatalf("no error should happen when parse query, got %v", err)
+	}
+
+	expects := repository.Query{
+		Filters: []repository.Filter{
+			{Field: "score", Operator: repository.OpGt, Value: "30"},
+			{Field: "title", Operator: repository.OpEq, Value: "jinzhu"},
+			{Or: []repository.Filter{
+				{Field: "ID", Operator: repository.OpIn, Value: "1,2"},
+				{Field: "published_at", Operator: repository.OpIsNull, Value: "true"},
+			}},
+		},
+		Sorts: []repository.Sort{{Column: "score", Desc: true}, {Column: "title"}},
+	}
+
+	if !reflect.DeepEqual(query, expects) {
+		t.Errorf("query expects %+v, got %+v", expects, query)
+	}
+
+	if _, err := repository.ParseQuery(url.Values{"filter[a][b][c]": {"1"}}); !errors.Is(err, repository.ErrInvalidFilter) {
+		t.Errorf("expects ErrInvalidFilter, got %v", err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	ctx := context.Background()
+	posts := repository.NewGenericRepository[Post, uint](openDB(t, &Post{}), logger.Discard)
+
+	now := time.Now()
+	for _, post := range []Post{
+		{Title: "go", Score: 10, PublishedAt: &now},
+		{Title: "gorm", Score: 40},
+		{Title: "rust", Score: 50, PublishedAt: &now},
+		{Title: "zig", Score: 20},
+	} {
+		if err := posts.Create(ctx, &post); err != nil {
+			t.Fatalf("no error should happen when create, got %v", err)
+		}
+	}
+
+	results := []struct {
+		Query string
+		IDs   []uint
+	}{
+		{"filter[score][gt]=30&sort=-score", []uint{3, 2}},
+		{"filter[Score][between]=10,20&sort=ID", []uint{1, 4}},
+		{"filter[title][like]=go%25&sort=title", []uint{1, 2}},
+		{"filter[title][nin]=go,zig&sort=-title", []uint{3, 2}},
+		{"filter[published_at][null]=false&sort=ID", []uint{1, 3}},
+		{"filter[score][lt]=30&filter[or][title]=rust&filter[or][published_at][null]=true&sort=ID", []uint{4}},
+	}
+
+	for _, result := range results {
+		values, _ := url.ParseQuery(result.Query)
+		query, err := repository.ParseQuery(values)
+		if err != nil {
+			t.Fatalf("no error should happen when parse %v, got %v", result.Query, err)
+		}
+
+		found, err := posts.FindAll(ctx, posts.Filter(query))
+		if err != nil {
+			t.Fatalf("no error should happen when filter %v, got %v", result.Query, err)
+		}
+
+		var ids []uint
+		for _, post := range found {
+			ids = append(ids, post.ID)
+		}
+
+		if !reflect.DeepEqual(ids, result.IDs) {
+			t.Errorf("%v expects %v, got %v", result.Query, result.IDs, ids)
+		}
+	}
+}
+
+func TestFilterRejected(t *testing.T) {
+	ctx := context.Background()
+	posts := repository.NewGenericRepository[Post, uint](openDB(t, &Post{}), logger.Discard)
+
+	results := []struct {
+		Name  string
+		Query repository.Query
+	}{
+		{"NotFilterableField", repository.Query{Filters: []repository.Filter{{Field: "password", Value: "secret"}}}},
+		{"NotFilterableNestedField", repository.Query{Filters: []repository.Filter{{Or: []repository.Filter{{Field: "Password", Value: "secret"}}}}}},
+		{"NotSortableField", repository.Query{Sorts: []repository.Sort{{Column: "published_at"}}}},
+		{"UnknownField", repository.Query{Filters: []repository.Filter{{Field: "deleted_at", Operator: repository.OpIsNull, Value: true}}}},
+		{"UnknownOperator", repository.Query{Filters: []repository.Filter{{Field: "score", Operator: "regexp", Value: "1"}}}},
+		{"LikeOnNumber", repository.Query{Filters: []repository.Filter{{Field: "score", Operator: repository.OpLike, Value: "1%"}}}},
+		{"LikeWithoutString", repository.Query{Filters: []repository.Filter{{Field: "title", Operator: repository.OpLike, Value: 1}}}},
+		{"InvalidNumber", repository.Query{Filters: []repository.Filter{{Field: "score", Value: "1; DROP TABLE posts"}}}},
+		{"InvalidTime", repository.Query{Filters: []repository.Filter{{Field: "published_at", Operator: repository.OpGt, Value: "yesterday"}}}},
+		{"InvalidInValue", repository.Query{Filters: []repository.Filter{{Field: "score", Operator: repository.OpIn, Value: "1,two"}}}},
+		{"BetweenOneValue", repository.Query{Filters: []repository.Filter{{Field: "score", Operator: repository.OpBetween, Value: "1"}}}},
+		{"NullWithoutBoolean", repository.Query{Filters: []repository.Filter{{Field: "published_at", Operator: repository.OpIsNull, Value: "maybe"}}}},
+		{"GroupWithoutFilters", repository.Query{Filters: []repository.Filter{{}}}},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			if _, err := posts.FindAll(ctx, posts.Filter(result.Query)); !errors.Is(err, repository.ErrInvalidFilter) {
+				t.Errorf("expects ErrInvalidFilter, got %v", err)
+			}
+		})
+	}
+}
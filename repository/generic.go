@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/schema"
+)
+
+type txContextKey struct{}
+
+// WithTx returns a copy of ctx making generic repositories run on tx
+func WithTx(ctx context.Context, tx *database.DB) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction stored by WithTx
+func TxFromContext(ctx context.Context) (*database.DB, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*database.DB)
+	return tx, ok && tx != nil
+}
+
+// GenericRepository is a type-safe repository of model T whose primary key is ID, models with a composite primary
+// key use a struct ID having fields named like the primary fields of T
+//
+//	users := repository.NewGenericRepository[User, uint](db, logger.Default)
+//	user, err := users.FindByID(ctx, 1, func(db *database.DB) *database.DB {
+//		return db.Preload("Orders")
+//	})
+type GenericRepository[T any, ID comparable] struct {
+	base *Repositories
+}
+
+// NewGenericRepository returns a new generic repository of T
+func NewGenericRepository[T any, ID comparable](db *database.DB, logger logger.Interface, defaultJoins ...string) *GenericRepository[T, ID] {
+	return &GenericRepository[T, ID]{
+		base: &Repositories{
+			defaultJoins: defaultJoins,
+			logger:       logger,
+			db:           db,
+			ctx:          context.Background(),
+		},
+	}
+}
+
+// DB returns the db used for ctx with default joins, the transaction of ctx if any
+func (r *GenericRepository[T, ID]) DB(ctx context.Context) *database.DB {
+	dbConn := r.base.db
+	if tx, ok := TxFromContext(ctx); ok {
+		dbConn = tx
+	}
+
+	dbConn = dbConn.WithContext(ctx)
+	for _, join := range r.base.defaultJoins {
+		dbConn = dbConn.Joins(join)
+	}
+	return dbConn
+}
+
+func (r *GenericRepository[T, ID]) FindByID(ctx context.Context, id ID, scopes ...func(*database.DB) *database.DB) (*T, error) {
+	r.base.logger.Info(ctx, "Executing FindByID on %T with ID %v", r, id)
+
+	cond, err := r.primaryKeyCondition(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var target T
+	res := r.DB(ctx).Scopes(scopes...).Clauses(cond).First(&target)
+	if err := r.handleOneError(ctx, res); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+func (r *GenericRepository[T, ID]) FindByIDs(ctx context.Context, ids []ID, scopes ...func(*database.DB) *database.DB) ([]T, error) {
+	r.base.logger.Info(ctx, "Executing FindByIDs on %T with IDs %v", r, ids)
+
+	targets := []T{}
+	if len(ids) == 0 {
+		return targets, nil
+	}
+
+	s, err := r.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	var cond clause.Expression
+	if len(s.PrimaryFields) == 1 {
+		values := make([]interface{}, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, id)
+		}
+		cond = clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: s.PrimaryFields[0].DBName}, Values: values}
+	} else {
+		conds := make([]clause.Expression, 0, len(ids))
+		for _, id := range ids {
+			idCond, err := r.primaryKeyCondition(id)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, idCond)
+		}
+		cond = clause.Or(conds...)
+	}
+
+	res := r.DB(ctx).Scopes(scopes...).Clauses(cond).Find(&targets)
+	return targets, r.handleError(ctx, res)
+}
+
+func (r *GenericRepository[T, ID]) FindAll(ctx context.Context, scopes ...func(*database.DB) *database.DB) ([]T, error) {
+	r.base.logger.Info(ctx, "Executing FindAll on %T", r)
+
+	targets := []T{}
+	res := r.DB(ctx).Scopes(scopes...).Find(&targets)
+	return targets, r.handleError(ctx, res)
+}
+
+func (r *GenericRepository[T, ID]) Create(ctx context.Context, target *T) error {
+	r.base.logger.Info(ctx, "Executing Create on %T", target)
+
+	res := r.DB(ctx).Create(target)
+	return r.handleError(ctx, res)
+}
+
+func (r *GenericRepository[T, ID]) CreateMany(ctx context.Context, targets []T) error {
+	r.base.logger.Info(ctx, "Executing CreateMany on %T", targets)
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	res := r.DB(ctx).Create(&targets)
+	return r.handleError(ctx, res)
+}
+
+// Update updates all fields of target by its primary key, zero values included, primary keys and fields tracking
+// creation time are kept
+func (r *GenericRepository[T, ID]) Update(ctx context.Context, target *T) error {
+	r.base.logger.Info(ctx, "Executing Update on %T", target)
+
+	s, err := r.Schema()
+	if err != nil {
+		return err
+	}
+
+	omits := make([]string, 0, len(s.PrimaryFields)+1)
+	for _, field := range s.Fields {
+		if field.DBName != "" && (field.PrimaryKey || field.AutoCreateTime > 0) {
+			omits = append(omits, field.DBName)
+		}
+	}
+
+	res := r.DB(ctx).Select("*").Omit(omits...).Updates(target)
+	return r.handleError(ctx, res)
+}
+
+func (r *GenericRepository[T, ID]) Delete(ctx context.Context, id ID) error {
+	r.base.logger.Info(ctx, "Executing Delete on %T with ID %v", r, id)
+
+	cond, err := r.primaryKeyCondition(id)
+	if err != nil {
+		return err
+	}
+
+	res := r.DB(ctx).Clauses(cond).Delete(new(T))
+	return r.handleError(ctx, res)
+}
+
+func (r *GenericRepository[T, ID]) Exists(ctx context.Context, scopes ...func(*database.DB) *database.DB) (bool, error) {
+	r.base.logger.Info(ctx, "Executing Exists on %T", r)
+
+	var exists bool
+	res := r.DB(ctx).Model(new(T)).Scopes(scopes...).Exists(&exists)
+	return exists, r.handleError(ctx, res)
+}
+
+func (r *GenericRepository[T, ID]) Count(ctx context.Context, scopes ...func(*database.DB) *database.DB) (int64, error) {
+	r.base.logger.Info(ctx, "Executing Count on %T", r)
+
+	var count int64
+	res := r.DB(ctx).Model(new(T)).Scopes(scopes...).Count(&count)
+	return count, r.handleError(ctx, res)
+}
+
+// Schema returns the parsed schema of T
+func (r *GenericRepository[T, ID]) Schema() (*schema.Schema, error) {
+	stmt := &database.Statement{DB: r.base.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// primaryKeyCondition returns the condition matching id with primary fields of T
+func (r *GenericRepository[T, ID]) primaryKeyCondition(id ID) (clause.Expression, error) {
+	s, err := r.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(s.PrimaryFields) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", database.ErrPrimaryKeyRequired, s.Name)
+	case 1:
+		return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: s.PrimaryFields[0].DBName}, Value: id}, nil
+	}
+
+	idValue := reflect.Indirect(reflect.ValueOf(id))
+	if idValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s has a composite primary key, %T should be a struct", ErrInvalidID, s.Name, id)
+	}
+
+	conds := make([]clause.Expression, 0, len(s.PrimaryFields))
+	for _, field := range s.PrimaryFields {
+		value := idValue.FieldByName(field.Name)
+		if !value.IsValid() {
+			return nil, fmt.Errorf("%w: %T missing primary field %s", ErrInvalidID, id, field.Name)
+		}
+		conds = append(conds, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value.Interface()})
+	}
+	return clause.And(conds...), nil
+}
+
+func (r *GenericRepository[T, ID]) handleError(ctx context.Context, res *database.DB) error {
+	return r.withContext(ctx).HandleError(res)
+}
+
+func (r *GenericRepository[T, ID]) handleOneError(ctx context.Context, res *database.DB) error {
+	return r.withContext(ctx).HandleOneError(res)
+}
+
+// withContext returns base repositories logging with ctx
+func (r *GenericRepository[T, ID]) withContext(ctx context.Context) *Repositories {
+	base := *r.base
+	base.ctx = ctx
+	return &base
+}
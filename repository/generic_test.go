@@ -0,0 +1,132 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/repository"
+)
+
+type Article struct {
+	ID        uint
+	Title     string
+	Views     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Membership struct {
+	GroupID uint `database:"primaryKey"`
+	UserID  uint `database:"primaryKey"`
+	Role    string
+}
+
+type MembershipID struct {
+	GroupID uint
+	UserID  uint
+}
+
+func TestGenericUpdate(t *testing.T) {
+	ctx := context.Background()
+	articles := repository.NewGenericRepository[Article, uint](openDB(t, &Article{}), logger.Discard)
+
+	article := Article{Title: "draft", Views: 10}
+	if err := articles.Create(ctx, &article); err != nil {
+		t.Fatalf("no error should happen when create, got %v", err)
+	}
+
+	updated := Article{ID: article.ID, Title: "published"}
+	if err := articles.Update(ctx, &updated); err != nil {
+		t.Fatalf("no error should happen when update, got %v", err)
+	}
+
+	result, err := articles.FindByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("no error should happen when find, got %v", err)
+	}
+
+	if result.Title != "published" || result.Views != 0 {
+		t.Errorf("all fields should be updated, got %+v", result)
+	}
+
+	if !result.CreatedAt.Equal(article.CreatedAt) {
+		t.Errorf("created at shouldn't be updated, expects %v, got %v", article.CreatedAt, result.CreatedAt)
+	}
+
+	if result.UpdatedAt.Before(article.UpdatedAt) {
+		t.Errorf("updated at should be updated, got %v", result.UpdatedAt)
+	}
+
+	if err := articles.Update(ctx, &Article{Title: "missing"}); !errors.Is(err, database.ErrMissingWhereClause) {
+		t.Errorf("update without primary key should fail, got %v", err)
+	}
+}
+
+func TestGenericCompositeID(t *testing.T) {
+	ctx := context.Background()
+	memberships := repository.NewGenericRepository[Membership, MembershipID](openDB(t, &Membership{}), logger.Discard)
+
+	if err := memberships.CreateMany(ctx, []Membership{{1, 1, "owner"}, {1, 2, "member"}, {2, 1, "member"}}); err != nil {
+		t.Fatalf("no error should happen when create, got %v", err)
+	}
+
+	result, err := memberships.FindByID(ctx, MembershipID{GroupID: 1, UserID: 2})
+	if err != nil || result.Role != "member" {
+		t.Errorf("membership should be found, got %+v, error %v", result, err)
+	}
+
+	results, err := memberships.FindByIDs(ctx, []MembershipID{{1, 1}, {2, 1}, {3, 3}})
+	if err != nil || len(results) != 2 {
+		t.Errorf("2 memberships should be found, got %+v, error %v", results, err)
+	}
+
+	if err := memberships.Update(ctx, &Membership{GroupID: 1, UserID: 2, Role: "owner"}); err != nil {
+		t.Fatalf("no error should happen when update, got %v", err)
+	}
+
+	if count, _ := memberships.Count(ctx, func(db *database.DB) *database.DB { return db.Where("role = ?", "owner") }); count != 2 {
+		t.Errorf("membership should be updated by its composite key, got %v owners", count)
+	}
+
+	if err := memberships.Delete(ctx, MembershipID{GroupID: 1, UserID: 1}); err != nil {
+		t.Fatalf("no error should happen when delete, got %v", err)
+	}
+
+	if _, err := memberships.FindByID(ctx, MembershipID{GroupID: 1, UserID: 1}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("deleted membership shouldn't be found, got %v", err)
+	}
+
+	type partialID struct{ GroupID uint }
+	invalid := repository.NewGenericRepository[Membership, partialID](openDB(t, &Membership{}), logger.Discard)
+	if _, err := invalid.FindByID(ctx, partialID{GroupID: 1}); !errors.Is(err, repository.ErrInvalidID) {
+		t.Errorf("expects ErrInvalidID, got %v", err)
+	}
+}
+
+func TestGenericWithTx(t *testing.T) {
+	db := openDB(t, &Article{})
+	articles := repository.NewGenericRepository[Article, uint](db, logger.Discard)
+
+	err := db.Transaction(func(tx *database.DB) error {
+		ctx := repository.WithTx(context.Background(), tx)
+		if err := articles.Create(ctx, &Article{Title: "rolled back"}); err != nil {
+			return err
+		}
+
+		if exists, _ := articles.Exists(ctx); !exists {
+			t.Errorf("article should be created within the transaction")
+		}
+		return errors.New("rollback")
+	})
+	if err == nil {
+		t.Fatalf("transaction should be rolled back")
+	}
+
+	if count, _ := articles.Count(context.Background()); count != 0 {
+		t.Errorf("article should be rolled back, got %v", count)
+	}
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/schema"
+)
+
+// DefaultPageSize page size used when none is given
+const DefaultPageSize = 20
+
+// Page result of offset pagination, Page starts from 1
+type Page[T any] struct {
+	Items    []T
+	Total    int64
+	Page     int
+	PageSize int
+	HasNext  bool
+}
+
+// FindPage returns page of the models matching scopes with the total count, models are ordered by primary key unless
+// scopes order them
+func (r *GenericRepository[T, ID]) FindPage(ctx context.Context, page, pageSize int, scopes ...func(*database.DB) *database.DB) (*Page[T], error) {
+	r.base.logger.Info(ctx, "Executing FindPage on %T with page %d size %d", r, page, pageSize)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	result := &Page[T]{Items: []T{}, Page: page, PageSize: pageSize}
+	res := r.DB(ctx).Model(new(T)).Scopes(scopes...).Count(&result.Total)
+	if err := r.handleError(ctx, res); err != nil {
+		return nil, err
+	}
+
+	if offset := (page - 1) * pageSize; int64(offset) < result.Total {
+		res = r.DB(ctx).Scopes(scopes...).Scopes(orderByPrimaryKey).Limit(pageSize).Offset(offset).Find(&result.Items)
+		if err := r.handleError(ctx, res); err != nil {
+			return nil, err
+		}
+	}
+
+	result.HasNext = int64(page*pageSize) < result.Total
+	return result, nil
+}
+
+// orderByPrimaryKey orders by primary key if db isn't ordered, so pages don't overlap
+func orderByPrimaryKey(db *database.DB) *database.DB {
+	if _, ok := db.Statement.Clauses["ORDER BY"]; ok {
+		return db
+	}
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}})
+}
+
+// Sort sort key of cursor pagination, Column is a field name or column name of the model
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// CursorOptions options of cursor pagination, at most one of After and Before should be set
+type CursorOptions struct {
+	// Sorts sort keys, primary keys are appended to make the order unique. Columns shouldn't be NULL
+	Sorts []Sort
+	// Limit defaults to DefaultPageSize
+	Limit int
+	// After returns rows after the cursor, e.g. NextCursor of the previous page
+	After string
+	// Before returns rows before the cursor, e.g. PrevCursor of the previous page
+	Before string
+}
+
+// CursorPage result of cursor pagination, cursors are empty if there is no next or previous page
+type CursorPage[T any] struct {
+	Items      []T
+	NextCursor string
+	PrevCursor string
+}
+
+type cursorKey struct {
+	field *schema.Field
+	desc  bool
+}
+
+// FindCursorPage returns the models matching scopes after or before a cursor, ordered by opts.Sorts
+func (r *GenericRepository[T, ID]) FindCursorPage(ctx context.Context, opts CursorOptions, scopes ...func(*database.DB) *database.DB) (*CursorPage[T], error) {
+	r.base.logger.Info(ctx, "Executing FindCursorPage on %T with %+v", r, opts.Sorts)
+
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultPageSize
+	}
+
+	keys, err := r.cursorKeys(opts.Sorts)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, backward := opts.After, false
+	if opts.Before != "" {
+		cursor, backward = opts.Before, true
+	}
+
+	tx := r.DB(ctx).Scopes(scopes...)
+	if cursor != "" {
+		values, err := decodeCursor(cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Clauses(keysetCondition(keys, values, backward))
+	}
+
+	for _, key := range keys {
+		tx = tx.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: key.field.DBName},
+			Desc:   key.desc != backward,
+		})
+	}
+
+	items := make([]T, 0, opts.Limit+1)
+	if err := r.handleError(ctx, tx.Limit(opts.Limit+1).Find(&items)); err != nil {
+		return nil, err
+	}
+
+	more := len(items) > opts.Limit
+	if more {
+		items = items[:opts.Limit]
+	}
+
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	result := &CursorPage[T]{Items: items}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	hasNext, hasPrev := more, cursor != ""
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+
+	if hasNext {
+		if result.NextCursor, err = encodeCursor(ctx, keys, &items[len(items)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	if hasPrev {
+		if result.PrevCursor, err = encodeCursor(ctx, keys, &items[0]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// cursorKeys returns fields of sorts followed by primary fields not sorted yet
+func (r *GenericRepository[T, ID]) cursorKeys(sorts []Sort) ([]cursorKey, error) {
+	s, err := r.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]cursorKey, 0, len(sorts)+len(s.PrimaryFields))
+	sorted := map[*schema.Field]bool{}
+	for _, sort := range sorts {
+		field := s.LookUpField(sort.Column)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("%w: %s", database.ErrInvalidField, sort.Column)
+		}
+
+		if !sorted[field] {
+			sorted[field] = true
+			keys = append(keys, cursorKey{field: field, desc: sort.Desc})
+		}
+	}
+
+	if len(s.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("%w: %s", database.ErrPrimaryKeyRequired, s.Name)
+	}
+
+	// primary keys follow the direction of the last sort key
+	desc := len(keys) > 0 && keys[len(keys)-1].desc
+	for _, field := range s.PrimaryFields {
+		if !sorted[field] {
+			keys = append(keys, cursorKey{field: field, desc: desc})
+		}
+	}
+	return keys, nil
+}
+
+// keysetCondition returns the condition of rows after values of keys, comparisons are reversed for backward pagination
+func keysetCondition(keys []cursorKey, values []interface{}, backward bool) clause.Expression {
+	columns := make([]clause.OrderByColumn, 0, len(keys))
+	for _, key := range keys {
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: key.field.DBName},
+			Desc:   key.desc != backward,
+		})
+	}
+	return clause.Keyset(columns, values)
+}
+
+func encodeCursor(ctx context.Context, keys []cursorKey, item interface{}) (string, error) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(item))
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i], _ = key.field.ValueOf(ctx, reflectValue)
+	}
+
+	bytes, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// decodeCursor decodes cursor values into the types of keys' fields
+func decodeCursor(cursor string, keys []cursorKey) ([]interface{}, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(bytes, &raws); err != nil || len(raws) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		value := reflect.New(key.field.FieldType)
+		if err := json.Unmarshal(raws[i], value.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, nil
+}
@@ -0,0 +1,175 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/repository"
+)
+
+func seedArticles(t *testing.T, views ...int) *repository.GenericRepository[Article, uint] {
+	articles := repository.NewGenericRepository[Article, uint](openDB(t, &Article{}), logger.Discard)
+	for _, v := range views {
+		if err := articles.Create(context.Background(), &Article{Title: "article", Views: v}); err != nil {
+			t.Fatalf("no error should happen when create, got %v", err)
+		}
+	}
+	return articles
+}
+
+func ids(articles []Article) (results []uint) {
+	for _, article := range articles {
+		results = append(results, article.ID)
+	}
+	return
+}
+
+func TestFindPage(t *testing.T) {
+	ctx := context.Background()
+	articles := seedArticles(t, 1, 2, 3, 4, 5)
+
+	results := []struct {
+		Page    int
+		IDs     []uint
+		HasNext bool
+	}{
+		{0, []uint{1, 2}, true},
+		{2, []uint{3, 4}, true},
+		{3, []uint{5}, false},
+		{4, nil, false},
+	}
+
+	for _, result := range results {
+		page, err := articles.FindPage(ctx, result.Page, 2, func(db *database.DB) *database.DB { return db.Order("id") })
+		if err != nil {
+			t.Fatalf("no error should happen when find page %v, got %v", result.Page, err)
+		}
+
+		if page.Total != 5 || page.HasNext != result.HasNext || !reflect.DeepEqual(ids(page.Items), result.IDs) {
+			t.Errorf("page %v expects %v, has next %v, got %+v", result.Page, result.IDs, result.HasNext, page)
+		}
+	}
+
+	page, err := articles.FindPage(ctx, 1, 2, func(db *database.DB) *database.DB { return db.Where("views > ?", 3) })
+	if err != nil || page.Total != 2 || page.HasNext || len(page.Items) != 2 {
+		t.Errorf("page should be filtered by scopes, got %+v, error %v", page, err)
+	}
+}
+
+func TestFindPageOrder(t *testing.T) {
+	ctx := context.Background()
+	articles := seedArticles(t, 1, 2, 3)
+
+	// records SQL of the count and the page
+	var sqls []string
+	articles.DB(ctx).Callback().Query().After("*").Register("test:record_sql", func(db *database.DB) {
+		sqls = append(sqls, db.Statement.SQL.String())
+	})
+
+	page, err := articles.FindPage(ctx, 1, 2)
+	if err != nil || !reflect.DeepEqual(ids(page.Items), []uint{1, 2}) {
+		t.Fatalf("page should be found, got %+v, error %v", page, err)
+	}
+
+	if len(sqls) != 2 || !strings.Contains(sqls[1], "ORDER BY `articles`.`id`") {
+		t.Errorf("pages should be ordered by primary key, got %v", sqls)
+	}
+
+	sqls = nil
+	page, err = articles.FindPage(ctx, 1, 2, func(db *database.DB) *database.DB { return db.Order("views DESC") })
+	if err != nil || !reflect.DeepEqual(ids(page.Items), []uint{3, 2}) {
+		t.Fatalf("page should be ordered by scopes, got %+v, error %v", page, err)
+	}
+
+	if len(sqls) != 2 || strings.Contains(sqls[1], "`articles`.`id`") {
+		t.Errorf("pages ordered by scopes shouldn't be ordered by primary key, got %v", sqls)
+	}
+}
+
+func TestFindCursorPage(t *testing.T) {
+	ctx := context.Background()
+	articles := seedArticles(t, 3, 1, 3, 2, 3, 1)
+	opts := repository.CursorOptions{Sorts: []repository.Sort{{Column: "Views", Desc: true}}, Limit: 2}
+
+	var (
+		pages    [][]uint
+		previous []string
+	)
+	for {
+		page, err := articles.FindCursorPage(ctx, opts)
+		if err != nil {
+			t.Fatalf("no error should happen when find cursor page, got %v", err)
+		}
+		pages = append(pages, ids(page.Items))
+		previous = append(previous, page.PrevCursor)
+
+		if page.NextCursor == "" {
+			break
+		}
+		opts.After = page.NextCursor
+	}
+
+	if expects := [][]uint{{5, 3}, {1, 4}, {6, 2}}; !reflect.DeepEqual(pages, expects) {
+		t.Fatalf("pages expects %v, got %v", expects, pages)
+	}
+
+	if previous[0] != "" {
+		t.Errorf("first page shouldn't have previous cursor")
+	}
+
+	page, err := articles.FindCursorPage(ctx, repository.CursorOptions{Sorts: opts.Sorts, Limit: 2, Before: previous[2]})
+	if err != nil || !reflect.DeepEqual(ids(page.Items), pages[1]) || page.NextCursor == "" || page.PrevCursor == "" {
+		t.Errorf("previous page expects %v, got %+v, error %v", pages[1], page, err)
+	}
+
+	page, err = articles.FindCursorPage(ctx, repository.CursorOptions{Sorts: opts.Sorts, Limit: 2, Before: page.PrevCursor})
+	if err != nil || !reflect.DeepEqual(ids(page.Items), pages[0]) || page.PrevCursor != "" {
+		t.Errorf("first page expects %v, got %+v, error %v", pages[0], page, err)
+	}
+}
+
+func TestFindCursorPageWithScopes(t *testing.T) {
+	ctx := context.Background()
+	articles := seedArticles(t, 3, 1, 3, 2, 3, 1)
+	popular := func(db *database.DB) *database.DB { return db.Where("views >= ?", 3) }
+
+	page, err := articles.FindCursorPage(ctx, repository.CursorOptions{Limit: 1}, popular)
+	if err != nil || !reflect.DeepEqual(ids(page.Items), []uint{1}) {
+		t.Fatalf("first page expects [1], got %+v, error %v", page, err)
+	}
+
+	// the keyset of the primary key is ANDed with the scopes
+	page, err = articles.FindCursorPage(ctx, repository.CursorOptions{Limit: 5, After: page.NextCursor}, popular)
+	if err != nil || !reflect.DeepEqual(ids(page.Items), []uint{3, 5}) {
+		t.Errorf("next page expects [3 5], got %+v, error %v", page, err)
+	}
+}
+
+func TestFindCursorPageErrors(t *testing.T) {
+	ctx := context.Background()
+	articles := seedArticles(t, 1, 2)
+
+	if _, err := articles.FindCursorPage(ctx, repository.CursorOptions{After: "invalid"}); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("expects ErrInvalidCursor, got %v", err)
+	}
+
+	page, err := articles.FindCursorPage(ctx, repository.CursorOptions{Limit: 1})
+	if err != nil || page.NextCursor == "" {
+		t.Fatalf("next cursor should be returned")
+	}
+
+	// cursors are bound to sort keys
+	_, err = articles.FindCursorPage(ctx, repository.CursorOptions{Sorts: []repository.Sort{{Column: "Views"}}, After: page.NextCursor})
+	if !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("expects ErrInvalidCursor, got %v", err)
+	}
+
+	if _, err := articles.FindCursorPage(ctx, repository.CursorOptions{Sorts: []repository.Sort{{Column: "unknown"}}}); !errors.Is(err, database.ErrInvalidField) {
+		t.Errorf("expects ErrInvalidField, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+)
+
+const operationClauseName = "database:resolver_operation"
+
+// Operation overrides where a statement is routed to
+//
+//	db.Clauses(resolver.Write).First(&user) // read from sources
+type Operation string
+
+const (
+	// Write route statement to sources
+	Write Operation = "write"
+	// Read route statement to replicas
+	Read Operation = "read"
+)
+
+func (op Operation) ModifyStatement(stmt *database.Statement) {
+	stmt.Clauses[operationClauseName] = clause.Clause{Name: operationClauseName, Expression: op}
+}
+
+// Build implements clause.Expression, nothing is written to the statement
+func (op Operation) Build(clause.Builder) {}
+
+// Use runs statements on the sources of the resolver group of data, a model or table name, transactions begun on them
+// run on the group
+//
+//	db.Clauses(resolver.Use(&Order{})).Transaction(func(tx *database.DB) error { ... })
+func Use(data interface{}) clause.Expression {
+	return use{data: data}
+}
+
+type use struct {
+	data interface{}
+}
+
+func (u use) ModifyStatement(stmt *database.Statement) {
+	r, ok := stmt.DB.Plugins[(&Resolver{}).Name()].(*Resolver)
+	if !ok {
+		stmt.AddError(fmt.Errorf("%w: resolver isn't registered", database.ErrInvalidValue))
+		return
+	}
+
+	table, err := tableOf(stmt.DB, u.data)
+	if err != nil {
+		stmt.AddError(err)
+		return
+	}
+
+	if g := r.lookUp(table); g != nil {
+		stmt.ConnPool = g.source()
+	}
+}
+
+// Build implements clause.Expression, nothing is written to the statement
+func (use) Build(clause.Builder) {}
@@ -0,0 +1,38 @@
+package resolver
+
+import (
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/driver005/database"
+)
+
+// Policy chooses one of the connection pools of a resolver group
+type Policy interface {
+	Resolve([]database.ConnPool) database.ConnPool
+}
+
+// PolicyFunc function as Policy
+type PolicyFunc func([]database.ConnPool) database.ConnPool
+
+func (f PolicyFunc) Resolve(connPools []database.ConnPool) database.ConnPool {
+	return f(connPools)
+}
+
+// RandomPolicy chooses a random connection pool
+type RandomPolicy struct{}
+
+func (RandomPolicy) Resolve(connPools []database.ConnPool) database.ConnPool {
+	if len(connPools) == 1 {
+		return connPools[0]
+	}
+	return connPools[rand.Intn(len(connPools))]
+}
+
+// RoundRobinPolicy returns a policy choosing connection pools in turn
+func RoundRobinPolicy() Policy {
+	var count uint64
+	return PolicyFunc(func(connPools []database.ConnPool) database.ConnPool {
+		return connPools[(atomic.AddUint64(&count, 1)-1)%uint64(len(connPools))]
+	})
+}
@@ -0,0 +1,301 @@
+package resolver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/driver005/database"
+)
+
+// ErrCrossGroupTransaction statements of a transaction target a table of another resolver group, transactions can't
+// span the sources of groups, begin them on the group with Use
+var ErrCrossGroupTransaction = errors.New("resolver: transaction can't span resolver groups")
+
+var (
+	readSQLRegexp  = regexp.MustCompile(`(?i)^\s*(select|with)\b`)
+	writeSQLRegexp = regexp.MustCompile(`(?i)\b(insert|update|delete|merge)\b`)
+)
+
+// Config sources and replicas of a resolver group, writes are routed to Sources and reads to Replicas
+//
+// Sources defaults to the primary connection pool of the db, Replicas defaults to Sources and Policy defaults to
+// RandomPolicy
+type Config struct {
+	Sources  []database.Dialector
+	Replicas []database.Dialector
+	Policy   Policy
+}
+
+type registration struct {
+	config Config
+	datas  []interface{}
+}
+
+type group struct {
+	primary  bool
+	sources  []database.ConnPool
+	replicas []database.ConnPool
+	policy   Policy
+}
+
+// owner returns the group owning the sources of g, nil for groups using the primary connection pool
+func (g *group) owner() *group {
+	if g == nil || g.primary {
+		return nil
+	}
+	return g
+}
+
+// source chooses a source of g, transactions begun on it keep the group
+func (g *group) source() database.ConnPool {
+	if g.primary {
+		return g.policy.Resolve(g.sources)
+	}
+	return &connPool{ConnPool: g.policy.Resolve(g.sources), group: g}
+}
+
+// connPool source connection pool of a resolver group
+type connPool struct {
+	database.ConnPool
+	group *group
+}
+
+func (p *connPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (database.ConnPool, error) {
+	var (
+		tx  database.ConnPool
+		err error
+	)
+
+	switch beginner := p.ConnPool.(type) {
+	case database.TxBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	case database.ConnPoolBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	default:
+		return nil, database.ErrInvalidTransaction
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{ConnPool: tx, group: p.group}, nil
+}
+
+// transaction transaction begun on a source of a resolver group
+type transaction struct {
+	database.ConnPool
+	group *group
+}
+
+func (tx *transaction) Commit() error {
+	return tx.ConnPool.(database.TxCommitter).Commit()
+}
+
+func (tx *transaction) Rollback() error {
+	return tx.ConnPool.(database.TxCommitter).Rollback()
+}
+
+func (tx *transaction) StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	return tx.ConnPool.(database.Tx).StmtContext(ctx, stmt)
+}
+
+// Resolver plugin splitting reads and writes between connection pools, statements inside a transaction or on a
+// pinned connection keep using it. Transactions run on the sources of one group, begin them with Use for groups with
+// their own sources, statements of a transaction targeting another group fail with ErrCrossGroupTransaction
+//
+//	db.Use(resolver.Register(resolver.Config{
+//		Replicas: []database.Dialector{postgres.Open(replicaDSN)},
+//	}).Register(resolver.Config{
+//		Sources: []database.Dialector{postgres.Open(ordersDSN)},
+//		Policy:  resolver.RoundRobinPolicy(),
+//	}, &Order{}, "order_items"))
+type Resolver struct {
+	registrations []registration
+	global        *group
+	groups        map[string]*group
+}
+
+// Register returns a resolver with config, see Resolver.Register
+func Register(config Config, datas ...interface{}) *Resolver {
+	return (&Resolver{}).Register(config, datas...)
+}
+
+// Register registers config for models or table names in datas, config without datas applies to everything else
+func (r *Resolver) Register(config Config, datas ...interface{}) *Resolver {
+	r.registrations = append(r.registrations, registration{config: config, datas: datas})
+	return r
+}
+
+func (r *Resolver) Name() string {
+	return "database:resolver"
+}
+
+func (r *Resolver) Initialize(db *database.DB) error {
+	r.groups = map[string]*group{}
+	for _, registration := range r.registrations {
+		g, err := r.compile(db, registration.config)
+		if err != nil {
+			return err
+		}
+
+		if len(registration.datas) == 0 {
+			r.global = g
+		}
+
+		for _, data := range registration.datas {
+			table, err := tableOf(db, data)
+			if err != nil {
+				return err
+			}
+			r.groups[table] = g
+		}
+	}
+
+	for _, err := range []error{
+		db.Callback().Create().Before("*").Register("database:resolver", r.switchSource),
+		db.Callback().Query().Before("*").Register("database:resolver", r.switchReplica),
+		db.Callback().Update().Before("*").Register("database:resolver", r.switchSource),
+		db.Callback().Delete().Before("*").Register("database:resolver", r.switchSource),
+		db.Callback().Row().Before("*").Register("database:resolver", r.switchReplica),
+		db.Callback().Raw().Before("*").Register("database:resolver", r.switchSource),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) compile(db *database.DB, config Config) (*group, error) {
+	g := &group{policy: config.Policy}
+	if g.policy == nil {
+		g.policy = RandomPolicy{}
+	}
+
+	var err error
+	if g.sources, err = r.connPools(db, config.Sources); err != nil {
+		return nil, err
+	} else if len(g.sources) == 0 {
+		g.primary = true
+		g.sources = []database.ConnPool{db.ConnPool}
+	}
+
+	if g.replicas, err = r.connPools(db, config.Replicas); err != nil {
+		return nil, err
+	} else if len(g.replicas) == 0 {
+		g.replicas = g.sources
+	}
+	return g, nil
+}
+
+func (r *Resolver) connPools(db *database.DB, dialectors []database.Dialector) ([]database.ConnPool, error) {
+	connPools := make([]database.ConnPool, 0, len(dialectors))
+	for _, dialector := range dialectors {
+		conn, err := database.Open(dialector, &database.Config{
+			Logger:               db.Logger,
+			NowFunc:              db.NowFunc,
+			PrepareStmt:          db.PrepareStmt,
+			DisableAutomaticPing: db.DisableAutomaticPing,
+		})
+		if err != nil {
+			return nil, err
+		}
+		connPools = append(connPools, conn.ConnPool)
+	}
+	return connPools, nil
+}
+
+func (r *Resolver) switchSource(db *database.DB) {
+	r.resolve(db.Statement, Write)
+}
+
+// switchReplica routes reads to replicas, locking reads and raw SQL writing rows, e.g: db.Raw("UPDATE ... RETURNING"),
+// are routed to sources
+func (r *Resolver) switchReplica(db *database.DB) {
+	if isWrite(db.Statement) {
+		r.resolve(db.Statement, Write)
+	} else {
+		r.resolve(db.Statement, Read)
+	}
+}
+
+func isWrite(stmt *database.Statement) bool {
+	if _, ok := stmt.Clauses["FOR"]; ok {
+		return true
+	}
+
+	if sql := stmt.SQL.String(); sql != "" {
+		return !readSQLRegexp.MatchString(sql) || writeSQLRegexp.MatchString(sql)
+	}
+	return false
+}
+
+func (r *Resolver) lookUp(table string) *group {
+	if g, ok := r.groups[table]; ok {
+		return g
+	}
+	return r.global
+}
+
+func (r *Resolver) resolve(stmt *database.Statement, op Operation) {
+	table := stmt.Table
+	if table == "" && stmt.Schema != nil {
+		table = stmt.Schema.Table
+	}
+	g := r.lookUp(table)
+
+	connPool := stmt.ConnPool
+	if tx, ok := connPool.(*database.PreparedStmtTX); ok {
+		connPool = tx.Tx
+	}
+
+	switch connPool := connPool.(type) {
+	case *transaction:
+		checkTransaction(stmt, table, connPool.group, g)
+		return
+	case database.TxCommitter:
+		checkTransaction(stmt, table, nil, g)
+		return
+	case *sql.Conn:
+		return
+	}
+
+	if g == nil {
+		return
+	}
+
+	if c, ok := stmt.Clauses[operationClauseName]; ok {
+		if o, ok := c.Expression.(Operation); ok {
+			op = o
+		}
+	}
+
+	if op == Write {
+		stmt.ConnPool = g.source()
+	} else {
+		stmt.ConnPool = g.policy.Resolve(g.replicas)
+	}
+}
+
+// checkTransaction fails statements of transactions on owner targeting tables of other groups, raw SQL without table
+// runs as is
+func checkTransaction(stmt *database.Statement, table string, owner, g *group) {
+	if table != "" && g.owner() != owner {
+		stmt.AddError(fmt.Errorf("%w: table %v", ErrCrossGroupTransaction, table))
+	}
+}
+
+func tableOf(db *database.DB, data interface{}) (string, error) {
+	if table, ok := data.(string); ok {
+		return table, nil
+	}
+
+	stmt := &database.Statement{DB: db}
+	if err := stmt.Parse(data); err != nil {
+		return "", err
+	}
+	return stmt.Table, nil
+}
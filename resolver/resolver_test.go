@@ -0,0 +1,273 @@
+package resolver_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+	"github.com/driver005/database/driver/sqlite"
+	"github.com/driver005/database/logger"
+	"github.com/driver005/database/resolver"
+)
+
+type User struct {
+	ID   uint
+	Name string
+}
+
+type Order struct {
+	ID   uint
+	Name string
+}
+
+type pool struct {
+	database.ConnPool
+	name string
+}
+
+// open opens a sqlite database named name, its users and orders are named after it to tell where statements ran
+func open(t *testing.T, dir, name string) (*database.DB, database.Dialector) {
+	dsn := filepath.Join(dir, name+".db") + "?_pragma=busy_timeout(5000)"
+	db, err := database.Open(sqlite.Open(dsn), &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database %v, got error %v", name, err)
+	}
+
+	if err := db.AutoMigrate(&User{}, &Order{}); err != nil {
+		t.Fatalf("failed to migrate database %v, got error %v", name, err)
+	}
+
+	if err := db.Create(&User{Name: name}).Error; err != nil {
+		t.Fatalf("failed to seed database %v, got error %v", name, err)
+	}
+
+	if err := db.Create(&Order{Name: name}).Error; err != nil {
+		t.Fatalf("failed to seed database %v, got error %v", name, err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db, sqlite.Open(dsn)
+}
+
+type databases struct {
+	db, primary, orders *database.DB
+}
+
+func openDB(t *testing.T, policy resolver.Policy) databases {
+	dir := t.TempDir()
+	primary, source := open(t, dir, "primary")
+	_, replica1 := open(t, dir, "replica1")
+	_, replica2 := open(t, dir, "replica2")
+	orders, ordersSource := open(t, dir, "orders")
+
+	db, err := database.Open(source, &database.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	err = db.Use(resolver.Register(resolver.Config{
+		Replicas: []database.Dialector{replica1, replica2},
+		Policy:   policy,
+	}).Register(resolver.Config{
+		Sources: []database.Dialector{ordersSource},
+	}, &Order{}))
+	if err != nil {
+		t.Fatalf("failed to register resolver, got error %v", err)
+	}
+	return databases{db: db, primary: primary, orders: orders}
+}
+
+func firstName(t *testing.T, db *database.DB, value interface{}) string {
+	var name string
+	if err := db.Model(value).Order("id").Limit(1).Pluck("name", &name).Error; err != nil {
+		t.Fatalf("no error should happen when query, got %v", err)
+	}
+	return name
+}
+
+func exists(db *database.DB, value interface{}, name string) bool {
+	var count int64
+	db.Model(value).Where("name = ?", name).Count(&count)
+	return count == 1
+}
+
+func TestRouting(t *testing.T) {
+	dbs := openDB(t, resolver.RoundRobinPolicy())
+
+	if name := firstName(t, dbs.db, &User{}); name != "replica1" {
+		t.Errorf("reads should be routed to replicas, got %v", name)
+	}
+
+	if name := firstName(t, dbs.db.Clauses(resolver.Write), &User{}); name != "primary" {
+		t.Errorf("reads with Write should be routed to sources, got %v", name)
+	}
+
+	if name := firstName(t, dbs.db, &Order{}); name != "orders" {
+		t.Errorf("reads of orders should be routed to the orders group, got %v", name)
+	}
+
+	if err := dbs.db.Create(&User{Name: "created"}).Error; err != nil {
+		t.Fatalf("no error should happen when create user, got %v", err)
+	}
+
+	if !exists(dbs.primary, &User{}, "created") {
+		t.Errorf("writes should be routed to sources")
+	}
+
+	if err := dbs.db.Create(&Order{Name: "created"}).Error; err != nil {
+		t.Fatalf("no error should happen when create order, got %v", err)
+	}
+
+	if !exists(dbs.orders, &Order{}, "created") || exists(dbs.primary, &Order{}, "created") {
+		t.Errorf("writes of orders should be routed to the orders group")
+	}
+}
+
+func TestRoutingRawAndLocking(t *testing.T) {
+	dbs := openDB(t, resolver.RoundRobinPolicy())
+
+	var name string
+	if err := dbs.db.Raw("SELECT name FROM users ORDER BY id LIMIT 1").Scan(&name).Error; err != nil || name != "replica1" {
+		t.Errorf("raw reads should be routed to replicas, got %v, error %v", name, err)
+	}
+
+	// sqlite doesn't lock rows, check the pool locking reads are routed to instead
+	stmt := dbs.db.Session(&database.Session{DryRun: true}).Clauses(clause.Locking{Strength: "UPDATE"}).Find(&[]User{}).Statement
+	if stmt.ConnPool != dbs.db.ConnPool {
+		t.Errorf("locking reads should be routed to sources")
+	}
+
+	if err := dbs.db.Raw("UPDATE users SET name = ? WHERE id = 1 RETURNING name", "updated").Scan(&name).Error; err != nil {
+		t.Fatalf("no error should happen when update, got %v", err)
+	}
+
+	if !exists(dbs.primary, &User{}, "updated") {
+		t.Errorf("raw updates should be routed to sources")
+	}
+
+	rows, err := dbs.db.Raw("INSERT INTO users (name) VALUES (?) RETURNING id", "inserted").Rows()
+	if err != nil {
+		t.Fatalf("no error should happen when insert, got %v", err)
+	}
+	rows.Close()
+
+	if !exists(dbs.primary, &User{}, "inserted") {
+		t.Errorf("raw inserts should be routed to sources")
+	}
+
+	if err := dbs.db.Raw("WITH t AS (SELECT 1) DELETE FROM users WHERE name = ? RETURNING id", "inserted").Scan(&name).Error; err != nil {
+		t.Fatalf("no error should happen when delete, got %v", err)
+	}
+
+	if exists(dbs.primary, &User{}, "inserted") {
+		t.Errorf("raw deletes with common table expressions should be routed to sources")
+	}
+}
+
+func TestRoundRobinPolicy(t *testing.T) {
+	dbs := openDB(t, resolver.RoundRobinPolicy())
+
+	for idx, expected := range []string{"replica1", "replica2", "replica1", "replica2"} {
+		if name := firstName(t, dbs.db, &User{}); name != expected {
+			t.Errorf("#%v read expects %v, got %v", idx, expected, name)
+		}
+	}
+
+	policy := resolver.RoundRobinPolicy()
+	pools := []database.ConnPool{pool{name: "a"}, pool{name: "b"}, pool{name: "c"}}
+	for idx, expected := range []string{"a", "b", "c", "a"} {
+		if name := policy.Resolve(pools).(pool).name; name != expected {
+			t.Errorf("#%v pool expects %v, got %v", idx, expected, name)
+		}
+	}
+}
+
+func TestRandomPolicy(t *testing.T) {
+	pools := []database.ConnPool{pool{name: "a"}, pool{name: "b"}}
+	if name := (resolver.RandomPolicy{}).Resolve(pools[:1]).(pool).name; name != "a" {
+		t.Errorf("the only pool should be chosen, got %v", name)
+	}
+
+	chosen := map[string]int{}
+	for i := 0; i < 200; i++ {
+		chosen[(resolver.RandomPolicy{}).Resolve(pools).(pool).name]++
+	}
+
+	if len(chosen) != 2 {
+		t.Errorf("all pools should be chosen, got %v", chosen)
+	}
+
+	dbs := openDB(t, nil)
+	for i := 0; i < 20; i++ {
+		if name := firstName(t, dbs.db, &User{}); name != "replica1" && name != "replica2" {
+			t.Fatalf("reads should be routed to replicas, got %v", name)
+		}
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	dbs := openDB(t, nil)
+
+	err := dbs.db.Transaction(func(tx *database.DB) error {
+		if name := firstName(t, tx, &User{}); name != "primary" {
+			t.Errorf("reads in transactions should run on the transaction, got %v", name)
+		}
+		return tx.Create(&User{Name: "in transaction"}).Error
+	})
+	if err != nil || !exists(dbs.primary, &User{}, "in transaction") {
+		t.Errorf("transaction should be committed on sources, got error %v", err)
+	}
+
+	err = dbs.db.Transaction(func(tx *database.DB) error {
+		return tx.Create(&Order{Name: "on primary"}).Error
+	})
+	if !errors.Is(err, resolver.ErrCrossGroupTransaction) {
+		t.Errorf("orders can't be written by transactions of the primary, got %v", err)
+	}
+
+	err = dbs.db.Clauses(resolver.Use(&Order{})).Transaction(func(tx *database.DB) error {
+		if err := tx.Create(&Order{Name: "in transaction"}).Error; err != nil {
+			return err
+		}
+
+		if !exists(tx, &Order{}, "in transaction") {
+			t.Errorf("reads in transactions should run on the transaction")
+		}
+
+		tx.Transaction(func(tx *database.DB) error {
+			tx.Create(&Order{Name: "rolled back"})
+			return errors.New("rollback")
+		})
+
+		if err := tx.Create(&User{Name: "on orders"}).Error; !errors.Is(err, resolver.ErrCrossGroupTransaction) {
+			t.Errorf("users can't be written by transactions of orders, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("no error should happen when commit, got %v", err)
+	}
+
+	if !exists(dbs.orders, &Order{}, "in transaction") || exists(dbs.orders, &Order{}, "rolled back") {
+		t.Errorf("transaction should be committed on the orders group")
+	}
+
+	if exists(dbs.primary, &Order{}, "in transaction") || exists(dbs.primary, &User{}, "on orders") {
+		t.Errorf("transaction of orders shouldn't write to the primary")
+	}
+
+	tx := dbs.db.Clauses(resolver.Use("orders")).Begin()
+	if err := tx.Create(&Order{Name: "rolled back"}).Error; err != nil {
+		t.Fatalf("no error should happen when create order, got %v", err)
+	}
+
+	if err := tx.Rollback().Error; err != nil || exists(dbs.orders, &Order{}, "rolled back") {
+		t.Errorf("transaction should be rolled back, got error %v", err)
+	}
+}
@@ -95,6 +95,61 @@ func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []int
 	}
 }
 
+// lookUpFields returns the readable fields matching columns, with the relation field of joined columns named like
+// `Relation__column`, values of unmatched columns are set to *sql.RawBytes
+func lookUpFields(sch *schema.Schema, columns []string, values []interface{}) ([]*schema.Field, [][2]*schema.Field) {
+	var (
+		fields     = make([]*schema.Field, len(columns))
+		joinFields [][2]*schema.Field
+	)
+
+	matchedFieldCount := make(map[string]int, len(columns))
+	for idx, column := range columns {
+		if field := sch.LookUpField(column); field != nil && field.Readable {
+			fields[idx] = field
+			if count, ok := matchedFieldCount[column]; ok {
+				// handle duplicate fields
+				for _, selectField := range sch.Fields {
+					if selectField.DBName == column && selectField.Readable {
+						if count == 0 {
+							matchedFieldCount[column]++
+							fields[idx] = selectField
+							break
+						}
+						count--
+					}
+				}
+			} else {
+				matchedFieldCount[column] = 1
+			}
+		} else if names := strings.Split(column, "__"); len(names) > 1 {
+			if rel, ok := sch.Relationships.Relations[names[0]]; ok {
+				if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
+					fields[idx] = field
+
+					if len(joinFields) == 0 {
+						joinFields = make([][2]*schema.Field, len(columns))
+					}
+					joinFields[idx] = [2]*schema.Field{rel.Field, field}
+					continue
+				}
+			}
+			values[idx] = &sql.RawBytes{}
+		} else {
+			values[idx] = &sql.RawBytes{}
+		}
+	}
+	return fields, joinFields
+}
+
+// nullableElem returns the value pointed by ptr, or the zero value if ptr is nil
+func nullableElem(ptr reflect.Value) reflect.Value {
+	if ptr.IsNil() {
+		return reflect.Zero(ptr.Type().Elem())
+	}
+	return ptr.Elem()
+}
+
 // ScanMode scan data mode
 type ScanMode uint8
 
@@ -199,42 +254,7 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 
 			// Not Pluck
 			if sch != nil {
-				matchedFieldCount := make(map[string]int, len(columns))
-				for idx, column := range columns {
-					if field := sch.LookUpField(column); field != nil && field.Readable {
-						fields[idx] = field
-						if count, ok := matchedFieldCount[column]; ok {
-							// handle duplicate fields
-							for _, selectField := range sch.Fields {
-								if selectField.DBName == column && selectField.Readable {
-									if count == 0 {
-										matchedFieldCount[column]++
-										fields[idx] = selectField
-										break
-									}
-									count--
-								}
-							}
-						} else {
-							matchedFieldCount[column] = 1
-						}
-					} else if names := strings.Split(column, "__"); len(names) > 1 {
-						if rel, ok := sch.Relationships.Relations[names[0]]; ok {
-							if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
-								fields[idx] = field
-
-								if len(joinFields) == 0 {
-									joinFields = make([][2]*schema.Field, len(columns))
-								}
-								joinFields[idx] = [2]*schema.Field{rel.Field, field}
-								continue
-							}
-						}
-						values[idx] = &sql.RawBytes{}
-					} else {
-						values[idx] = &sql.RawBytes{}
-					}
-				}
+				fields, joinFields = lookUpFields(sch, columns, values)
 			}
 		}
 
@@ -47,6 +47,12 @@ const (
 	String DataType = "string"
 	Time   DataType = "time"
 	Bytes  DataType = "bytes"
+	// Array slices and arrays of Bool, Int, Uint, Float, String or Time elements, e.g: []string
+	Array DataType = "array"
+	// Map maps of string keys and values, e.g: map[string]string
+	Map DataType = "map"
+	// JSON JSON documents, json of mysql and sqlite, jsonb of postgres, nvarchar(MAX) of sqlserver
+	JSON DataType = "json"
 )
 
 // Field is the representation of model schema's field
@@ -268,6 +274,23 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 	case reflect.Array, reflect.Slice:
 		if reflect.Indirect(fieldValue).Type().Elem() == ByteReflectType && field.DataType == "" {
 			field.DataType = Bytes
+		} else if field.DataType == "" && isScalarType(reflect.Indirect(fieldValue).Type().Elem()) {
+			field.DataType = Array
+		}
+	case reflect.Map:
+		if rt := reflect.Indirect(fieldValue).Type(); field.DataType == "" && rt.Key().Kind() == reflect.String &&
+			(rt.Elem().Kind() == reflect.String || (rt.Elem().Kind() == reflect.Ptr && rt.Elem().Elem().Kind() == reflect.String)) {
+			field.DataType = Map
+		}
+	}
+
+	// arrays and maps are serialized by the serializers of the dialect, e.g: postgres, see SetDataTypeSerializer,
+	// dialects without them don't support arrays and maps
+	if field.Serializer == nil && (field.DataType == Array || field.DataType == Map) {
+		if serializer, ok := dataTypeSerializer(schema.cacheStore, field.DataType); ok {
+			field.Serializer = serializer
+		} else {
+			field.DataType = ""
 		}
 	}
 
@@ -305,7 +328,7 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 
 	if val, ok := field.TagSettings["TYPE"]; ok {
 		switch DataType(strings.ToLower(val)) {
-		case Bool, Int, Uint, Float, String, Time, Bytes:
+		case Bool, Int, Uint, Float, String, Time, Bytes, JSON:
 			field.DataType = DataType(strings.ToLower(val))
 		default:
 			field.DataType = DataType(val)
@@ -473,6 +496,10 @@ func (field *Field) setupValuerAndSetter() {
 		field.ValueOf = func(ctx context.Context, v reflect.Value) (interface{}, bool) {
 			value, zero := oldValuerOf(ctx, v)
 			if zero {
+				// nil slices and maps are NULL, otherwise they are added to SQL as lists of values or rejected by drivers
+				if kind := reflect.ValueOf(value).Kind(); kind == reflect.Slice || kind == reflect.Map {
+					return nil, zero
+				}
 				return value, zero
 			}
 
@@ -487,7 +514,7 @@ func (field *Field) setupValuerAndSetter() {
 				Destination:     v,
 				Context:         ctx,
 				fieldValue:      value,
-			}, false
+			}, zero
 		}
 	}
 
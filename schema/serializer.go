@@ -30,6 +30,27 @@ func GetSerializer(name string) (serializer SerializerInterface, ok bool) {
 	return serializer, ok
 }
 
+// dataTypeSerializerKey key of the serializers of data types in cache stores
+type dataTypeSerializerKey struct{}
+
+// SetDataTypeSerializer sets the serializers of data types, e.g: Array and Map, for schemas parsed with cacheStore,
+// dialects serialize them for their databases only
+func SetDataTypeSerializer(cacheStore *sync.Map, serializerOf func(DataType) (SerializerInterface, bool)) {
+	cacheStore.Store(dataTypeSerializerKey{}, serializerOf)
+}
+
+// dataTypeSerializer returns the serializer of dataType set for cacheStore
+func dataTypeSerializer(cacheStore *sync.Map, dataType DataType) (SerializerInterface, bool) {
+	if cacheStore == nil {
+		return nil, false
+	}
+
+	if v, ok := cacheStore.Load(dataTypeSerializerKey{}); ok {
+		return v.(func(DataType) (SerializerInterface, bool))(dataType)
+	}
+	return nil, false
+}
+
 func init() {
 	RegisterSerializer("json", JSONSerializer{})
 	RegisterSerializer("unixtime", UnixSecondSerializer{})
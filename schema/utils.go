@@ -44,6 +44,25 @@ func ParseTagSetting(str string, sep string) map[string]string {
 	return settings
 }
 
+// isScalarType returns true if values of rt are stored in a single column, e.g: elements of array fields
+func isScalarType(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	case reflect.Array:
+		// uuids, e.g: [16]byte
+		return rt.Elem() == ByteReflectType
+	case reflect.Struct:
+		return rt.ConvertibleTo(TimeReflectType)
+	}
+	return false
+}
+
 func toColumns(val string) (results []string) {
 	if val != "" {
 		for _, v := range strings.Split(val, ",") {
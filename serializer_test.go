@@ -0,0 +1,34 @@
+package database_test
+
+import "testing"
+
+func TestSerializerNil(t *testing.T) {
+	db := openDB(t, &Contact{})
+	contacts := []Contact{{Name: "nil"}, {Name: "empty", Tags: []string{}}}
+	if err := db.Create(&contacts).Error; err != nil {
+		t.Fatalf("no error should happen when create contacts, got %v", err)
+	}
+
+	var names []string
+	if db.Model(&Contact{}).Where("tags IS NULL").Pluck("name", &names); len(names) != 1 || names[0] != "nil" {
+		t.Errorf("nil slices should be NULL, got %v", names)
+	}
+
+	if db.Model(&Contact{}).Where("tags = ?", "[]").Pluck("name", &names); len(names) != 1 || names[0] != "empty" {
+		t.Errorf("empty slices should be serialized, got %v", names)
+	}
+
+	var found []Contact
+	if db.Order("id").Find(&found); len(found) != 2 || found[0].Tags != nil || found[1].Tags == nil {
+		t.Errorf("nil and empty slices should be scanned back, got %+v", found)
+	}
+
+	if err := db.Model(&found[1]).Update("tags", []string(nil)).Error; err != nil {
+		t.Fatalf("no error should happen when update with nil, got %v", err)
+	}
+
+	var count int64
+	if db.Model(&Contact{}).Where("tags IS NULL").Count(&count); count != 2 {
+		t.Errorf("updated nil slices should be NULL, got %v", count)
+	}
+}
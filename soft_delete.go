@@ -1,15 +1,22 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/driver005/database/clause"
 	"github.com/driver005/database/schema"
 )
 
+// SoftDeleteTimeKey Statement.Settings key of the time records are soft deleted at, shared by records deleted with
+// their associations
+const SoftDeleteTimeKey = "database:soft_delete_time"
+
 type DeletedAt sql.NullTime
 
 // Scan implements the Scanner interface.
@@ -48,6 +55,222 @@ func (DeletedAt) QueryClauses(f *schema.Field) []clause.Interface {
 	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
 }
 
+func (DeletedAt) notDeletedValue(*schema.Field) interface{} {
+	return nil
+}
+
+func (DeletedAt) deletedValue(_ *schema.Field, now time.Time) interface{} {
+	return now
+}
+
+func (DeletedAt) deletedTime(_ *schema.Field, value interface{}) (time.Time, time.Duration, bool) {
+	deletedAt, ok := value.(DeletedAt)
+	return deletedAt.Time, 0, ok && deletedAt.Valid
+}
+
+// SoftDelete soft delete field storing unix seconds, records not deleted have 0 which, unlike NULL, works with
+// composite unique indexes. The tag setting softDelete stores unix milliseconds with milli, or 1 with flag
+//
+//	type User struct {
+//		ID        uint
+//		Email     string              `database:"uniqueIndex:idx_email_deleted"`
+//		DeletedAt database.SoftDelete `database:"uniqueIndex:idx_email_deleted;not null;default:0"`
+//	}
+//
+//	type Tag struct {
+//		ID        uint
+//		IsDeleted database.SoftDelete `database:"softDelete:flag;not null;default:0"`
+//	}
+//
+// Flags of boolean columns are SoftDeleteFlag
+type SoftDelete int64
+
+func (SoftDelete) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (SoftDelete) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteUpdateClause{Field: f}}
+}
+
+func (SoftDelete) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteDeleteClause{Field: f}}
+}
+
+func (SoftDelete) notDeletedValue(*schema.Field) interface{} {
+	return 0
+}
+
+func (SoftDelete) deletedValue(field *schema.Field, now time.Time) interface{} {
+	switch strings.ToLower(field.TagSettings["SOFTDELETE"]) {
+	case "flag":
+		return 1
+	case "milli":
+		return now.UnixNano() / 1e6
+	default:
+		return now.Unix()
+	}
+}
+
+func (SoftDelete) deletedTime(field *schema.Field, value interface{}) (time.Time, time.Duration, bool) {
+	deleted, ok := value.(SoftDelete)
+	if !ok || deleted == 0 {
+		return time.Time{}, 0, false
+	}
+
+	switch strings.ToLower(field.TagSettings["SOFTDELETE"]) {
+	case "flag":
+		return time.Time{}, 0, false
+	case "milli":
+		return time.Unix(0, int64(deleted)*1e6), time.Millisecond, true
+	default:
+		return time.Unix(int64(deleted), 0), time.Second, true
+	}
+}
+
+// SoftDeleteFlag soft delete field of boolean columns, records not deleted have false and deleted records true
+//
+//	type Tag struct {
+//		ID        uint
+//		IsDeleted database.SoftDeleteFlag `database:"not null;default:false"`
+//	}
+type SoftDeleteFlag bool
+
+// Scan implements the Scanner interface.
+func (n *SoftDeleteFlag) Scan(value interface{}) error {
+	var flag sql.NullBool
+	err := flag.Scan(value)
+	*n = SoftDeleteFlag(flag.Bool)
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n SoftDeleteFlag) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
+func (SoftDeleteFlag) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (SoftDeleteFlag) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteUpdateClause{Field: f}}
+}
+
+func (SoftDeleteFlag) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteDeleteClause{Field: f}}
+}
+
+func (SoftDeleteFlag) notDeletedValue(*schema.Field) interface{} {
+	return false
+}
+
+func (SoftDeleteFlag) deletedValue(*schema.Field, time.Time) interface{} {
+	return true
+}
+
+func (SoftDeleteFlag) deletedTime(*schema.Field, interface{}) (time.Time, time.Duration, bool) {
+	return time.Time{}, 0, false
+}
+
+// softDeleteValuer values of soft delete field types
+type softDeleteValuer interface {
+	notDeletedValue(field *schema.Field) interface{}
+	deletedValue(field *schema.Field, now time.Time) interface{}
+	// deletedTime returns the time a record was deleted at with its precision, false if value doesn't record it
+	deletedTime(field *schema.Field, value interface{}) (at time.Time, precision time.Duration, ok bool)
+}
+
+func softDeleteValues(field *schema.Field) softDeleteValuer {
+	if valuer, ok := reflect.New(field.IndirectFieldType).Elem().Interface().(softDeleteValuer); ok {
+		return valuer
+	}
+	return DeletedAt{}
+}
+
+// deletedWithin returns the condition of records of field deleted within precision after at
+func deletedWithin(field *schema.Field, at time.Time, precision time.Duration) clause.Expression {
+	var (
+		valuer   = softDeleteValues(field)
+		column   = clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+		from, to = valuer.deletedValue(field, at), valuer.deletedValue(field, at.Add(precision))
+	)
+
+	if precision == 0 || from == to {
+		return clause.Eq{Column: column, Value: from}
+	}
+	return clause.And(clause.Gte{Column: column, Value: from}, clause.Lte{Column: column, Value: to})
+}
+
+// softDeleteField returns the soft delete field of s
+func softDeleteField(s *schema.Schema) *schema.Field {
+	if s != nil {
+		for _, field := range s.Fields {
+			if _, ok := reflect.New(field.IndirectFieldType).Elem().Interface().(softDeleteValuer); ok && field.DBName != "" {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+// DeletedBy records who soft deleted a record, it is set to the value of WithDeletedBy when records of a model
+// having a soft delete field are deleted
+type DeletedBy sql.NullString
+
+// Scan implements the Scanner interface.
+func (n *DeletedBy) Scan(value interface{}) error {
+	return (*sql.NullString)(n).Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (n DeletedBy) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.String, nil
+}
+
+func (n DeletedBy) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.String)
+	}
+	return json.Marshal(nil)
+}
+
+func (n *DeletedBy) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		n.Valid = false
+		return nil
+	}
+	err := json.Unmarshal(b, &n.String)
+	if err == nil {
+		n.Valid = true
+	}
+	return err
+}
+
+type deletedByKey struct{}
+
+// WithDeletedBy returns a copy of ctx recording by in DeletedBy fields of records soft deleted with it
+//
+//	db.WithContext(database.WithDeletedBy(ctx, currentUser.Email)).Delete(&order)
+func WithDeletedBy(ctx context.Context, by string) context.Context {
+	return context.WithValue(ctx, deletedByKey{}, by)
+}
+
+// deletedByField returns the DeletedBy field of s
+func deletedByField(s *schema.Schema) *schema.Field {
+	if s != nil {
+		for _, field := range s.Fields {
+			if field.DBName != "" && field.IndirectFieldType == reflect.TypeOf(DeletedBy{}) {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
 type SoftDeleteQueryClause struct {
 	Field *schema.Field
 }
@@ -64,26 +287,30 @@ func (sd SoftDeleteQueryClause) MergeClause(*clause.Clause) {
 
 func (sd SoftDeleteQueryClause) ModifyStatement(stmt *Statement) {
 	if _, ok := stmt.Clauses["soft_delete_enabled"]; !ok && !stmt.Statement.Unscoped {
-		if c, ok := stmt.Clauses["WHERE"]; ok {
-			if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) >= 1 {
-				for _, expr := range where.Exprs {
-					if orCond, ok := expr.(clause.OrConditions); ok && len(orCond.Exprs) == 1 {
-						where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
-						c.Expression = where
-						stmt.Clauses["WHERE"] = c
-						break
-					}
-				}
-			}
-		}
-
+		groupOrConditions(stmt)
 		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
-			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: nil},
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: softDeleteValues(sd.Field).notDeletedValue(sd.Field)},
 		}})
 		stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
 	}
 }
 
+// groupOrConditions groups existing conditions when they contain OR, so conditions added later apply to all of them
+func groupOrConditions(stmt *Statement) {
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) >= 1 {
+			for _, expr := range where.Exprs {
+				if orCond, ok := expr.(clause.OrConditions); ok && len(orCond.Exprs) == 1 {
+					where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
+					c.Expression = where
+					stmt.Clauses["WHERE"] = c
+					break
+				}
+			}
+		}
+	}
+}
+
 func (DeletedAt) UpdateClauses(f *schema.Field) []clause.Interface {
 	return []clause.Interface{SoftDeleteUpdateClause{Field: f}}
 }
@@ -128,9 +355,22 @@ func (sd SoftDeleteDeleteClause) MergeClause(*clause.Clause) {
 
 func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *Statement) {
 	if stmt.SQL.Len() == 0 && !stmt.Statement.Unscoped {
-		curTime := stmt.DB.NowFunc()
-		stmt.AddClause(clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: curTime}})
-		stmt.SetColumn(sd.Field.DBName, curTime, true)
+		now, ok := stmt.Settings.Load(SoftDeleteTimeKey)
+		if !ok {
+			now = stmt.DB.NowFunc()
+		}
+
+		deletedValue := softDeleteValues(sd.Field).deletedValue(sd.Field, now.(time.Time))
+		set := clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: deletedValue}}
+		stmt.SetColumn(sd.Field.DBName, deletedValue, true)
+
+		if field := deletedByField(stmt.Schema); field != nil {
+			if by, ok := stmt.Context.Value(deletedByKey{}).(string); ok {
+				set = append(set, clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: by})
+				stmt.SetColumn(field.DBName, by, true)
+			}
+		}
+		stmt.AddClause(set)
 
 		if stmt.Schema != nil {
 			_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
@@ -0,0 +1,288 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+	"github.com/driver005/database/clause"
+)
+
+type Subscriber struct {
+	ID        uint
+	Email     string              `database:"size:64;uniqueIndex:idx_email_deleted"`
+	DeletedAt database.SoftDelete `database:"uniqueIndex:idx_email_deleted;not null;default:0"`
+	DeletedBy database.DeletedBy
+}
+
+type Label struct {
+	ID        uint
+	Name      string
+	IsDeleted database.SoftDelete `database:"softDelete:flag;not null;default:0"`
+}
+
+type Topic struct {
+	ID        uint
+	Name      string
+	IsDeleted database.SoftDeleteFlag `database:"not null;default:false"`
+}
+
+type Event struct {
+	ID        uint
+	Name      string
+	DeletedAt database.SoftDelete `database:"softDelete:milli"`
+}
+
+type Member struct {
+	ID        uint
+	Name      string
+	Addresses []MemberAddress
+	Roles     []Role `database:"many2many:member_roles"`
+	DeletedAt database.DeletedAt
+}
+
+type MemberAddress struct {
+	ID        uint
+	MemberID  uint
+	Street    string
+	DeletedAt database.SoftDelete `database:"not null;default:0"`
+}
+
+type Role struct {
+	ID   uint
+	Name string
+}
+
+type MemberRole struct {
+	MemberID  uint `database:"primaryKey"`
+	RoleID    uint `database:"primaryKey"`
+	DeletedAt database.DeletedAt
+}
+
+// openMemberDB opens a database of members, their join table of roles is soft deletable
+func openMemberDB(t *testing.T, config *database.Session) *database.DB {
+	db := openDB(t)
+	if err := db.SetupJoinTable(&Member{}, "Roles", &MemberRole{}); err != nil {
+		t.Fatalf("no error should happen when setup join table, got %v", err)
+	}
+
+	if err := db.AutoMigrate(&Member{}, &MemberAddress{}, &Role{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	return db.Session(config)
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := openDB(t, &Subscriber{}, &Label{}, &Event{})
+	now := time.Now()
+
+	subscriber, label, event := Subscriber{Email: "jinzhu@example.org"}, Label{Name: "label"}, Event{Name: "event"}
+	for _, value := range []interface{}{&subscriber, &label, &event} {
+		if err := db.Create(value).Error; err != nil {
+			t.Fatalf("no error should happen when create %T, got %v", value, err)
+		}
+
+		if err := db.WithContext(database.WithDeletedBy(context.Background(), "admin")).Delete(value).Error; err != nil {
+			t.Fatalf("no error should happen when delete %T, got %v", value, err)
+		}
+
+		var count int64
+		if db.Model(value).Count(&count); count != 0 {
+			t.Errorf("deleted %T shouldn't be found, got %v", value, count)
+		}
+
+		if db.Unscoped().Model(value).Count(&count); count != 1 {
+			t.Errorf("deleted %T should be found with Unscoped, got %v", value, count)
+		}
+	}
+
+	var (
+		deletedSubscriber Subscriber
+		deletedLabel      Label
+		deletedEvent      Event
+	)
+	db.Unscoped().First(&deletedSubscriber, subscriber.ID)
+	db.Unscoped().First(&deletedLabel, label.ID)
+	db.Unscoped().First(&deletedEvent, event.ID)
+
+	if seconds := int64(deletedSubscriber.DeletedAt); seconds < now.Unix()-1 || seconds > now.Unix()+5 {
+		t.Errorf("deleted at should be unix seconds, got %v", seconds)
+	}
+
+	if deletedSubscriber.DeletedBy.String != "admin" {
+		t.Errorf("deleted by should be set from context, got %+v", deletedSubscriber.DeletedBy)
+	}
+
+	if deletedLabel.IsDeleted != 1 {
+		t.Errorf("deleted flag should be 1, got %v", deletedLabel.IsDeleted)
+	}
+
+	if millis := int64(deletedEvent.DeletedAt); millis < now.UnixNano()/1e6-1000 || millis > now.UnixNano()/1e6+5000 {
+		t.Errorf("deleted at should be unix milliseconds, got %v", millis)
+	}
+
+	// records not deleted have 0, so composite unique indexes only apply to them
+	if err := db.Create(&Subscriber{Email: "jinzhu@example.org"}).Error; err != nil {
+		t.Errorf("no error should happen when recreate deleted subscriber, got %v", err)
+	}
+
+	if err := db.Create(&Subscriber{Email: "jinzhu@example.org"}).Error; err == nil {
+		t.Errorf("duplicated subscriber shouldn't be created")
+	}
+}
+
+func TestSoftDeleteFlag(t *testing.T) {
+	db := openDB(t, &Topic{})
+
+	topics := []Topic{{Name: "deleted"}, {Name: "kept"}}
+	if err := db.Create(&topics).Error; err != nil {
+		t.Fatalf("no error should happen when create topics, got %v", err)
+	}
+
+	if err := db.Delete(&topics[0]).Error; err != nil {
+		t.Fatalf("no error should happen when delete topic, got %v", err)
+	}
+
+	var names []string
+	if db.Model(&Topic{}).Order("id").Pluck("name", &names); len(names) != 1 || names[0] != "kept" {
+		t.Errorf("deleted topics shouldn't be found, got %v", names)
+	}
+
+	var deleted Topic
+	if err := db.Unscoped().First(&deleted, topics[0].ID).Error; err != nil || !deleted.IsDeleted {
+		t.Errorf("deleted flag should be true, got %v, error %v", deleted.IsDeleted, err)
+	}
+
+	if err := db.Restore(&Topic{}, topics[0].ID).Error; err != nil {
+		t.Fatalf("no error should happen when restore topic, got %v", err)
+	}
+
+	if db.Model(&Topic{}).Order("id").Pluck("name", &names); len(names) != 2 {
+		t.Errorf("restored topics should be found, got %v", names)
+	}
+}
+
+func TestSoftDeleteScopes(t *testing.T) {
+	db := openDB(t, &Label{})
+
+	labels := []Label{{Name: "deleted"}, {Name: "kept"}}
+	db.Create(&labels)
+	db.Delete(&labels[0])
+
+	var names []string
+	db.Model(&Label{}).Where("name = ?", "kept").Or("id = ?", labels[0].ID).Order("id").Pluck("name", &names)
+	if len(names) != 1 || names[0] != "kept" {
+		t.Errorf("deleted labels shouldn't be found by OR conditions, got %v", names)
+	}
+
+	if result := db.Model(&Label{}).Where("id = ?", labels[0].ID).Update("name", "updated"); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("deleted labels shouldn't be updated, got %v rows, error %v", result.RowsAffected, result.Error)
+	}
+
+	if result := db.Unscoped().Model(&Label{}).Where("id = ?", labels[0].ID).Update("name", "updated"); result.RowsAffected != 1 {
+		t.Errorf("deleted labels should be updated with Unscoped, got %v rows", result.RowsAffected)
+	}
+
+	if err := db.Unscoped().Delete(&labels[0]).Error; err != nil {
+		t.Fatalf("no error should happen when delete permanently, got %v", err)
+	}
+
+	var count int64
+	if db.Unscoped().Model(&Label{}).Count(&count); count != 1 {
+		t.Errorf("label should be deleted permanently with Unscoped, got %v", count)
+	}
+}
+
+func TestSoftDeleteAssociations(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *database.Session
+		joinRows int64
+	}{
+		{name: "default", config: &database.Session{}, joinRows: 0},
+		{name: "keep join rows", config: &database.Session{KeepJoinRowsOnSoftDelete: true}, joinRows: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := openMemberDB(t, test.config)
+			member := Member{Name: "jinzhu", Addresses: []MemberAddress{{Street: "first"}}, Roles: []Role{{Name: "admin"}}}
+			if err := db.Create(&member).Error; err != nil {
+				t.Fatalf("no error should happen when create member, got %v", err)
+			}
+
+			if err := db.Select(clause.Associations).Delete(&member).Error; err != nil {
+				t.Fatalf("no error should happen when delete member, got %v", err)
+			}
+
+			var (
+				deleted Member
+				address MemberAddress
+			)
+			db.Unscoped().First(&deleted, member.ID)
+			db.Unscoped().First(&address, member.Addresses[0].ID)
+			if !deleted.DeletedAt.Valid || int64(address.DeletedAt) != deleted.DeletedAt.Time.Unix() {
+				t.Errorf("addresses should be deleted at the time of the member, got %v and %v", address.DeletedAt, deleted.DeletedAt)
+			}
+
+			var count int64
+			if db.Model(&MemberRole{}).Count(&count); count != test.joinRows {
+				t.Errorf("join rows expects %v, got %v", test.joinRows, count)
+			}
+
+			if db.Model(&Role{}).Count(&count); count != 1 {
+				t.Errorf("roles shouldn't be deleted, got %v", count)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	db := openMemberDB(t, &database.Session{})
+	member := Member{Name: "jinzhu", Addresses: []MemberAddress{{Street: "first"}, {Street: "second"}}, Roles: []Role{{Name: "admin"}}}
+	if err := db.Create(&member).Error; err != nil {
+		t.Fatalf("no error should happen when create member, got %v", err)
+	}
+
+	// deleted before the member, so it isn't restored with it
+	if err := db.Session(&database.Session{NowFunc: func() time.Time {
+		return time.Now().Add(-time.Hour)
+	}}).Delete(&member.Addresses[1]).Error; err != nil {
+		t.Fatalf("no error should happen when delete address, got %v", err)
+	}
+
+	if err := db.Select(clause.Associations).Delete(&member).Error; err != nil {
+		t.Fatalf("no error should happen when delete member, got %v", err)
+	}
+
+	if err := db.Restore(&Member{}).Error; err != database.ErrMissingWhereClause {
+		t.Errorf("restore without conditions should be rejected, got %v", err)
+	}
+
+	if result := db.Restore(&MemberAddress{}, "street = ?", "unknown"); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("no records should be restored with conditions matching none, got %v rows, error %v", result.RowsAffected, result.Error)
+	}
+
+	if err := db.Restore(&Role{ID: 1}).Error; err == nil {
+		t.Errorf("models without soft delete fields can't be restored")
+	}
+
+	result := db.Select(clause.Associations).Restore(&Member{ID: member.ID})
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Fatalf("no error should happen when restore member, got %v rows, error %v", result.RowsAffected, result.Error)
+	}
+
+	var restored Member
+	if err := db.Preload("Addresses").Preload("Roles").First(&restored, member.ID).Error; err != nil {
+		t.Fatalf("restored member should be found, got %v", err)
+	}
+
+	if len(restored.Addresses) != 1 || restored.Addresses[0].Street != "first" {
+		t.Errorf("only addresses deleted with the member should be restored, got %+v", restored.Addresses)
+	}
+
+	if len(restored.Roles) != 1 || restored.Roles[0].Name != "admin" {
+		t.Errorf("join rows deleted with the member should be restored, got %+v", restored.Roles)
+	}
+}
@@ -0,0 +1,161 @@
+package database_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/driver005/database"
+)
+
+// events records events of hooks
+type events struct {
+	mux    sync.Mutex
+	events []string
+}
+
+func (e *events) add(format string, args ...interface{}) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.events = append(e.events, fmt.Sprintf(format, args...))
+}
+
+func (e *events) take() []string {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	events := e.events
+	e.events = nil
+	return events
+}
+
+var hookEvents = &events{}
+
+type Order struct {
+	ID    uint
+	Items []OrderItem
+}
+
+func (o *Order) AfterCommit(tx *database.DB) error {
+	hookEvents.add("order %d committed", o.ID)
+	return nil
+}
+
+func (o *Order) AfterRollback(tx *database.DB) error {
+	hookEvents.add("order %d rolled back", o.ID)
+	return nil
+}
+
+type OrderItem struct {
+	ID      uint
+	OrderID uint
+	Name    string `database:"unique"`
+}
+
+func (i *OrderItem) AfterCommit(tx *database.DB) error {
+	hookEvents.add("item %s committed", i.Name)
+	return nil
+}
+
+func TestTransactionHooks(t *testing.T) {
+	db := openDB(t)
+	var e events
+
+	db.AfterCommit(func() { e.add("without transaction") })
+	if got := e.take(); !reflect.DeepEqual(got, []string{"without transaction"}) {
+		t.Errorf("hooks should be called immediately without transaction, got %v", got)
+	}
+
+	db.Transaction(func(tx *database.DB) error {
+		tx.AfterCommit(func() { e.add("outer committed") })
+		tx.AfterRollback(func() { e.add("outer rolled back") })
+
+		tx.Transaction(func(tx *database.DB) error {
+			tx.AfterCommit(func() { e.add("kept committed") })
+			return nil
+		})
+
+		tx.Transaction(func(tx *database.DB) error {
+			tx.AfterCommit(func() { e.add("discarded committed") })
+			tx.AfterRollback(func() { e.add("savepoint rolled back") })
+			return errors.New("rollback savepoint")
+		})
+
+		// statements of new sessions share hooks of the transaction
+		tx.Session(&database.Session{NewDB: true}).AfterCommit(func() { e.add("new session committed") })
+
+		if got := e.take(); !reflect.DeepEqual(got, []string{"savepoint rolled back"}) {
+			t.Errorf("only hooks of the rolled back savepoint should be called, got %v", got)
+		}
+		return nil
+	})
+
+	if got := e.take(); !reflect.DeepEqual(got, []string{"outer committed", "kept committed", "new session committed"}) {
+		t.Errorf("commit hooks should be called after commit, got %v", got)
+	}
+
+	db.Transaction(func(tx *database.DB) error {
+		tx.AfterCommit(func() { e.add("committed") })
+		tx.AfterRollback(func() { e.add("rolled back") })
+		return errors.New("rollback")
+	})
+
+	if got := e.take(); !reflect.DeepEqual(got, []string{"rolled back"}) {
+		t.Errorf("rollback hooks should be called after rollback, got %v", got)
+	}
+}
+
+func TestTransactionHooksAfterFinished(t *testing.T) {
+	db := openDB(t)
+	var e events
+
+	tx := db.Begin()
+	tx.AfterCommit(func() { e.add("committed") })
+	tx.Commit()
+
+	// hooks of finished transactions aren't kept
+	tx.AfterCommit(func() { e.add("after finished") })
+	tx.AfterRollback(func() { e.add("rollback after finished") })
+	tx.Rollback()
+
+	if got := e.take(); !reflect.DeepEqual(got, []string{"committed", "after finished"}) {
+		t.Errorf("hooks of finished transactions should be called immediately, got %v", got)
+	}
+}
+
+func TestModelTransactionHooks(t *testing.T) {
+	db := openDB(t, &Order{}, &OrderItem{})
+	hookEvents.take()
+
+	order := Order{Items: []OrderItem{{Name: "apple"}}}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("no error should happen when create order, got %v", err)
+	}
+
+	// hooks of associations are called with the transaction of the owner
+	expects := []string{"item apple committed", fmt.Sprintf("order %d committed", order.ID)}
+	if got := hookEvents.take(); !reflect.DeepEqual(got, expects) {
+		t.Errorf("hooks expects %v got %v", expects, got)
+	}
+
+	// duplicated item fails the default transaction
+	duplicated := Order{Items: []OrderItem{{Name: "apple"}}}
+	if err := db.Create(&duplicated).Error; err == nil {
+		t.Fatalf("duplicated item should fail")
+	}
+
+	if got := hookEvents.take(); !reflect.DeepEqual(got, []string{fmt.Sprintf("order %d rolled back", duplicated.ID)}) {
+		t.Errorf("rollback hooks of order should be called, got %v", got)
+	}
+
+	var rolledBack Order
+	db.Transaction(func(tx *database.DB) error {
+		tx.Create(&rolledBack)
+		return errors.New("rollback")
+	})
+
+	if got := hookEvents.take(); !reflect.DeepEqual(got, []string{fmt.Sprintf("order %d rolled back", rolledBack.ID)}) {
+		t.Errorf("rollback hooks of order should be called, got %v", got)
+	}
+}
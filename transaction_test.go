@@ -0,0 +1,126 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/driver005/database"
+)
+
+type Account struct {
+	ID      uint
+	Balance int
+}
+
+func TestIsRetryableError(t *testing.T) {
+	results := []struct {
+		Err       error
+		Retryable bool
+	}{
+		{database.ErrSerializationFailure, true},
+		{database.ErrDeadlock, true},
+		{database.ErrLockTimeout, true},
+		{fmt.Errorf("wrapped: %w", database.ErrDeadlock), true},
+		{&database.TranslatedError{Err: database.ErrSerializationFailure, Cause: errors.New("40001")}, true},
+		{&database.TranslatedError{Err: database.ErrDuplicatedKey, Cause: errors.New("23505")}, false},
+		{database.ErrRecordNotFound, false},
+		{errors.New("failed"), false},
+	}
+
+	for _, result := range results {
+		if retryable := database.IsRetryableError(result.Err); retryable != result.Retryable {
+			t.Errorf("error %v retryable expects %v got %v", result.Err, result.Retryable, retryable)
+		}
+	}
+}
+
+func TestTransactionWithRetry(t *testing.T) {
+	db := openDB(t, &Account{})
+	retry := database.TransactionRetry{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	results := []struct {
+		Name     string
+		Failures int
+		Err      error
+		Attempts int
+	}{
+		{"Succeeded", 0, nil, 1},
+		{"RetriedSerializationFailure", 2, database.ErrSerializationFailure, 3},
+		{"RetriedDeadlock", 1, database.ErrDeadlock, 2},
+		{"ExceededAttempts", 5, database.ErrSerializationFailure, 3},
+		{"NotRetryable", 5, database.ErrDuplicatedKey, 1},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			db.Where("1 = 1").Delete(&Account{})
+
+			var attempts int
+			err := db.TransactionWithRetry(func(tx *database.DB) error {
+				attempts++
+				if err := tx.Create(&Account{Balance: 100}).Error; err != nil {
+					return err
+				}
+
+				if attempts <= result.Failures {
+					return result.Err
+				}
+				return nil
+			}, retry)
+
+			if attempts != result.Attempts {
+				t.Errorf("attempts expects %v got %v", result.Attempts, attempts)
+			}
+
+			var count int64
+			db.Model(&Account{}).Count(&count)
+			if result.Failures < result.Attempts {
+				if err != nil || count != 1 {
+					t.Errorf("transaction should be committed once, got %v accounts, error %v", count, err)
+				}
+			} else if !errors.Is(err, result.Err) || count != 0 {
+				t.Errorf("transaction should be rolled back with %v, got %v accounts, error %v", result.Err, count, err)
+			}
+		})
+	}
+}
+
+func TestTransactionWithRetryCanceled(t *testing.T) {
+	db := openDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var attempts int
+	err := db.WithContext(ctx).TransactionWithRetry(func(tx *database.DB) error {
+		attempts++
+		cancel()
+		return database.ErrDeadlock
+	}, database.TransactionRetry{MaxAttempts: 5, MinBackoff: time.Millisecond})
+
+	if attempts != 1 || !errors.Is(err, database.ErrDeadlock) {
+		t.Errorf("canceled transactions shouldn't be retried, got %v attempts, error %v", attempts, err)
+	}
+}
+
+func TestNestedTransactionWithRetry(t *testing.T) {
+	db := openDB(t, &Account{})
+	retry := database.TransactionRetry{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	var outer, inner int
+	err := db.TransactionWithRetry(func(tx *database.DB) error {
+		outer++
+		return tx.TransactionWithRetry(func(tx *database.DB) error {
+			inner++
+			if outer < 2 {
+				return database.ErrSerializationFailure
+			}
+			return tx.Create(&Account{Balance: 100}).Error
+		}, retry)
+	}, retry)
+
+	if err != nil || outer != 2 || inner != 2 {
+		t.Errorf("nested transactions should be retried with the outermost one, got %v outer, %v inner, error %v", outer, inner, err)
+	}
+}
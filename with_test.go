@@ -0,0 +1,89 @@
+package database_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/driver005/database"
+)
+
+type Employee struct {
+	ID        uint
+	Name      string
+	ManagerID *uint
+	Active    bool
+}
+
+// seedEmployees creates a ceo managing a manager of an engineer, and a consultant without manager
+func seedEmployees(t *testing.T, db *database.DB) {
+	var managerID *uint
+	for _, employee := range []*Employee{{Name: "ceo", Active: true}, {Name: "manager", Active: true}, {Name: "engineer"}} {
+		employee.ManagerID = managerID
+		if err := db.Create(employee).Error; err != nil {
+			t.Fatalf("no error should happen when create employee, got %v", err)
+		}
+		managerID = &employee.ID
+	}
+
+	if err := db.Create(&Employee{Name: "consultant", Active: true}).Error; err != nil {
+		t.Fatalf("no error should happen when create employee, got %v", err)
+	}
+}
+
+func TestWith(t *testing.T) {
+	db := openDB(t, &Employee{})
+	seedEmployees(t, db)
+
+	var names []string
+	err := db.With("active_employees", db.Model(&Employee{}).Where("active = ?", true)).
+		Table("active_employees").Order("id").Pluck("name", &names).Error
+	if err != nil || !reflect.DeepEqual(names, []string{"ceo", "manager", "consultant"}) {
+		t.Errorf("subqueries should be common table expressions, got %v, error %v", names, err)
+	}
+
+	var totals []struct {
+		ManagerID uint
+		Reports   int
+	}
+	err = db.With("reports (manager_id, reports)", db.Model(&Employee{}).Select("manager_id", "COUNT(*)").Where("manager_id IS NOT NULL").Group("manager_id")).
+		Table("reports").Order("manager_id").Find(&totals).Error
+	if err != nil || len(totals) != 2 || totals[0].ManagerID != 1 || totals[0].Reports != 1 {
+		t.Errorf("columns of common table expressions should be named, got %+v, error %v", totals, err)
+	}
+
+	err = db.With("inactive", "SELECT name FROM employees WHERE active = ?", false).Table("inactive").Pluck("name", &names).Error
+	if err != nil || !reflect.DeepEqual(names, []string{"engineer"}) {
+		t.Errorf("raw SQL should be common table expressions, got %v, error %v", names, err)
+	}
+}
+
+func TestWithRecursive(t *testing.T) {
+	db := openDB(t, &Employee{})
+	seedEmployees(t, db)
+
+	var names []string
+	err := db.WithRecursive("subordinates (id, name)",
+		"? UNION ALL ?",
+		db.Model(&Employee{}).Select("id", "name").Where("id = ?", 1),
+		db.Model(&Employee{}).Select("employees.id", "employees.name").Joins("JOIN subordinates ON employees.manager_id = subordinates.id"),
+	).Table("subordinates").Order("id").Pluck("name", &names).Error
+	if err != nil || !reflect.DeepEqual(names, []string{"ceo", "manager", "engineer"}) {
+		t.Errorf("subordinates should be found recursively, got %v, error %v", names, err)
+	}
+}
+
+func TestWithErrors(t *testing.T) {
+	db := openDB(t, &Employee{})
+
+	var names []string
+	for _, name := range []string{"", "active employees", "reports (manager_id", "reports.total"} {
+		if err := db.With(name, "SELECT name FROM employees").Table("reports").Pluck("name", &names).Error; !errors.Is(err, database.ErrInvalidData) {
+			t.Errorf("invalid name %q should be rejected, got %v", name, err)
+		}
+	}
+
+	if err := db.With("reports", 1).Table("reports").Pluck("name", &names).Error; !errors.Is(err, database.ErrInvalidData) {
+		t.Errorf("unsupported queries should be rejected, got %v", err)
+	}
+}